@@ -0,0 +1,151 @@
+package vessel
+
+import (
+	"log"
+
+	"github.com/xraph/go-utils/di"
+)
+
+// CaptiveMode decides what happens when a captive dependency is found.
+type CaptiveMode int
+
+const (
+	// CaptiveOff disables captive dependency detection (default).
+	CaptiveOff CaptiveMode = iota
+
+	// CaptiveWarn reports captive dependencies through the policy's
+	// OnCaptive callback but lets the registration through.
+	CaptiveWarn
+
+	// CaptiveError rejects registrations that create a captive dependency
+	// and makes Validate report them.
+	CaptiveError
+)
+
+// CaptivePolicy configures captive dependency detection.
+//
+// A dependency is captive when a singleton depends on a scoped or transient
+// service: the singleton is built once, so it holds on to a single instance
+// of a service that was meant to be short-lived. For scoped services this
+// also fails at runtime, since they cannot be resolved outside a scope.
+type CaptivePolicy struct {
+	Mode CaptiveMode
+
+	// AllowLazy exempts lazy dependencies. A Lazy wrapper resolves the
+	// service on each use rather than holding an instance, so it acts as a
+	// provider instead of capturing it.
+	AllowLazy bool
+
+	// OnCaptive receives captive dependencies in CaptiveWarn mode.
+	// Defaults to logging them with the standard logger.
+	OnCaptive func(dep CaptiveDependency)
+}
+
+// CaptiveDependency describes a longer-lived service depending on a
+// shorter-lived one.
+type CaptiveDependency struct {
+	Service             string
+	Lifecycle           string
+	Dependency          string
+	DependencyLifecycle string
+}
+
+// WithCaptivePolicy enables captive dependency detection. Registrations are
+// checked as they are added, in both directions, and Validate checks the
+// whole container.
+//
+// Example:
+//
+//	c := vessel.New(vessel.WithCaptivePolicy(vessel.CaptivePolicy{
+//	    Mode:      vessel.CaptiveError,
+//	    AllowLazy: true,
+//	}))
+func WithCaptivePolicy(policy CaptivePolicy) ContainerOption {
+	return func(o *containerOptions) {
+		o.captive = policy
+	}
+}
+
+// lifecycle returns the registration's lifecycle name.
+func (reg *serviceRegistration) lifecycle() string {
+	switch {
+	case reg.singleton:
+		return "singleton"
+	case reg.scoped:
+		return "scoped"
+	default:
+		return "transient"
+	}
+}
+
+// captures reports whether dependent captures target through the
+// dependency spec dep. AllowLazy exempts lazy dependencies.
+func (p CaptivePolicy) captures(dependent *serviceRegistration, dep di.Dep, target *serviceRegistration) bool {
+	if !dependent.singleton || target.singleton {
+		return false
+	}
+
+	return !p.AllowLazy || !dep.Mode.IsLazy()
+}
+
+// findCaptive returns the captive dependencies reg takes part in, either
+// as the dependent or as the dependency. c.mu must be held; registrations
+// in parent containers are looked up through their own locks.
+func (c *containerImpl) findCaptive(reg *serviceRegistration) []CaptiveDependency {
+	policy := c.options.captive
+
+	var found []CaptiveDependency
+
+	// reg depending on something shorter-lived
+	for _, dep := range reg.deps {
+		target, ok := c.services[dep.Name]
+		if !ok {
+			if parent := c.parentImpl(); parent != nil {
+				target, _, ok = parent.lookup(dep.Name)
+			}
+		}
+
+		if ok && policy.captures(reg, dep, target) {
+			found = append(found, newCaptiveDependency(reg, target))
+		}
+	}
+
+	// Existing services depending on reg
+	for _, name := range c.graph.order {
+		dependent, ok := c.services[name]
+		if !ok || name == reg.name {
+			continue
+		}
+
+		for _, dep := range dependent.deps {
+			if dep.Name == reg.name && policy.captures(dependent, dep, reg) {
+				found = append(found, newCaptiveDependency(dependent, reg))
+			}
+		}
+	}
+
+	return found
+}
+
+// newCaptiveDependency describes dependent capturing dep.
+func newCaptiveDependency(dependent, dep *serviceRegistration) CaptiveDependency {
+	return CaptiveDependency{
+		Service:             dependent.name,
+		Lifecycle:           dependent.lifecycle(),
+		Dependency:          dep.name,
+		DependencyLifecycle: dep.lifecycle(),
+	}
+}
+
+// warnCaptive reports captive dependencies in CaptiveWarn mode.
+func (p CaptivePolicy) warnCaptive(found []CaptiveDependency) {
+	for _, dep := range found {
+		if p.OnCaptive != nil {
+			p.OnCaptive(dep)
+
+			continue
+		}
+
+		log.Printf("vessel: %v", ErrCaptiveDependency(dep))
+	}
+}
@@ -0,0 +1,106 @@
+package vessel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xraph/go-utils/di"
+)
+
+func newServiceFactory(value string) Factory {
+	return func(c Vessel) (any, error) {
+		return &testService{value: value}, nil
+	}
+}
+
+func TestCaptive_OffByDefault(t *testing.T) {
+	c := New()
+
+	require.NoError(t, c.Register("request", newServiceFactory("r"), Scoped()))
+	require.NoError(t, c.Register("cache", newServiceFactory("c"), WithDependencies("request")))
+	assert.NoError(t, Validate(c))
+}
+
+func TestCaptive_ErrorAtRegistration(t *testing.T) {
+	c := New(WithCaptivePolicy(CaptivePolicy{Mode: CaptiveError}))
+
+	require.NoError(t, c.Register("request", newServiceFactory("r"), Scoped()))
+	require.NoError(t, c.Register("clock", newServiceFactory("t"), Transient()))
+
+	// Singleton registered after its scoped dependency
+	err := c.Register("cache", newServiceFactory("c"), WithDependencies("request"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCaptiveDependencySentinel)
+	assert.Contains(t, err.Error(), "singleton service 'cache' depends on scoped service 'request'")
+	assert.False(t, c.Has("cache"))
+
+	// Singleton registered before its transient dependency
+	require.NoError(t, c.Register("report", newServiceFactory("rep"), WithDependencies("metrics")))
+	err = c.Register("metrics", newServiceFactory("m"), Transient())
+	assert.ErrorIs(t, err, ErrCaptiveDependencySentinel)
+
+	// Shorter-lived services may depend on anything
+	assert.NoError(t, c.Register("handler", newServiceFactory("h"), Scoped(), WithDependencies("request", "clock")))
+}
+
+func TestCaptive_AllowLazy(t *testing.T) {
+	strict := New(WithCaptivePolicy(CaptivePolicy{Mode: CaptiveError}))
+	require.NoError(t, strict.Register("clock", newServiceFactory("t"), Transient()))
+	assert.Error(t, strict.Register("svc", newServiceFactory("s"), di.WithDeps(di.Lazy("clock"))))
+
+	lenient := New(WithCaptivePolicy(CaptivePolicy{Mode: CaptiveError, AllowLazy: true}))
+	require.NoError(t, lenient.Register("clock", newServiceFactory("t"), Transient()))
+	assert.NoError(t, lenient.Register("svc", newServiceFactory("s"), di.WithDeps(di.Lazy("clock"))))
+	assert.Error(t, lenient.Register("eager", newServiceFactory("e"), WithDependencies("clock")))
+}
+
+func TestCaptive_Warn(t *testing.T) {
+	var warnings []CaptiveDependency
+
+	c := New(WithCaptivePolicy(CaptivePolicy{
+		Mode:      CaptiveWarn,
+		OnCaptive: func(dep CaptiveDependency) { warnings = append(warnings, dep) },
+	}))
+
+	require.NoError(t, c.Register("request", newServiceFactory("r"), Scoped()))
+	require.NoError(t, c.Register("cache", newServiceFactory("c"), WithDependencies("request")))
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, CaptiveDependency{
+		Service:             "cache",
+		Lifecycle:           "singleton",
+		Dependency:          "request",
+		DependencyLifecycle: "scoped",
+	}, warnings[0])
+
+	// Validate warns again but does not fail
+	assert.NoError(t, Validate(c))
+	assert.Len(t, warnings, 2)
+}
+
+func TestCaptive_Validate(t *testing.T) {
+	parent := New()
+	require.NoError(t, parent.Register("request", newServiceFactory("r"), Scoped()))
+
+	// Registered without a policy, then checked through a child that has one
+	child := NewChild(parent, WithCaptivePolicy(CaptivePolicy{Mode: CaptiveError}))
+	err := child.Register("cache", newServiceFactory("c"), WithDependencies("request"))
+	assert.ErrorIs(t, err, ErrCaptiveDependencySentinel, "parent registrations are checked too")
+
+	c := New()
+	require.NoError(t, c.Register("request", newServiceFactory("r"), Scoped()))
+	require.NoError(t, c.Register("cache", newServiceFactory("c"), WithDependencies("request")))
+	c.(*containerImpl).options.captive = CaptivePolicy{Mode: CaptiveError}
+
+	err = Validate(c)
+	require.Error(t, err)
+
+	var report *ValidationError
+	require.True(t, errors.As(err, &report))
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, IssueCaptiveDependency, report.Issues[0].Kind)
+	assert.Equal(t, "cache", report.Issues[0].Service)
+	assert.Equal(t, "request", report.Issues[0].Dependency)
+}
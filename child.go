@@ -0,0 +1,82 @@
+package vessel
+
+// NewChild creates a container layered on top of parent.
+//
+// The child can register new services and shadow parent registrations by
+// reusing their names. Anything it doesn't register itself is resolved from
+// the parent, so parent singletons are shared rather than rebuilt.
+// Start, Stop and Health on the child only manage the child's own services.
+//
+// Example:
+//
+//	base := vessel.New()
+//	vessel.RegisterSingleton(base, "db", NewDatabase)
+//
+//	tenant := vessel.NewChild(base)
+//	vessel.RegisterSingleton(tenant, "config", NewTenantConfig) // child-only
+//	db, _ := vessel.Resolve[*Database](tenant, "db")            // from base
+func NewChild(parent Vessel, opts ...ContainerOption) Vessel {
+	child := newContainerImpl(opts...).(*containerImpl)
+	child.parent = parent
+
+	return child
+}
+
+// Parent returns the container this one falls back to, or nil for a root container.
+func (c *containerImpl) Parent() Vessel {
+	return c.parent
+}
+
+// parentImpl returns the parent when it is a vessel container.
+func (c *containerImpl) parentImpl() *containerImpl {
+	impl, _ := c.parent.(*containerImpl)
+
+	return impl
+}
+
+// lookup finds the registration for name in this container or its
+// ancestors, returning the container that owns it.
+func (c *containerImpl) lookup(name string) (*serviceRegistration, *containerImpl, bool) {
+	for current := c; current != nil; current = current.parentImpl() {
+		current.mu.RLock()
+		reg, exists := current.services[name]
+		current.mu.RUnlock()
+
+		if exists {
+			return reg, current, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// findType finds a type-based registration in this container or its ancestors.
+func (c *containerImpl) findType(key typeKey) (*typeRegistration, bool) {
+	for current := c; current != nil; current = current.parentImpl() {
+		if current.typeRegistry == nil {
+			continue
+		}
+
+		if reg, ok := current.typeRegistry.get(key); ok {
+			return reg, true
+		}
+	}
+
+	return nil, false
+}
+
+// findGroup collects a value group across this container and its ancestors.
+// The child's members come first, followed by each ancestor's.
+func (c *containerImpl) findGroup(group string) []*typeRegistration {
+	var regs []*typeRegistration
+
+	for current := c; current != nil; current = current.parentImpl() {
+		if current.typeRegistry == nil {
+			continue
+		}
+
+		regs = append(regs, current.typeRegistry.getGroup(group)...)
+	}
+
+	return regs
+}
@@ -0,0 +1,131 @@
+package vessel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChild_FallsBackToParent(t *testing.T) {
+	parent := New()
+	calls := 0
+
+	err := RegisterSingleton(parent, "db", func(c Vessel) (*testService, error) {
+		calls++
+
+		return &testService{value: "shared-db"}, nil
+	})
+	require.NoError(t, err)
+
+	child := NewChild(parent)
+
+	assert.True(t, child.Has("db"))
+
+	fromChild, err := Resolve[*testService](child, "db")
+	require.NoError(t, err)
+
+	fromParent, err := Resolve[*testService](parent, "db")
+	require.NoError(t, err)
+
+	assert.Same(t, fromParent, fromChild, "parent singletons are shared")
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "singleton", child.Inspect("db").Lifecycle)
+}
+
+func TestChild_ShadowsParent(t *testing.T) {
+	parent := New()
+	require.NoError(t, RegisterValue(parent, "config", &testService{value: "base"}))
+
+	child := NewChild(parent)
+	require.NoError(t, RegisterValue(child, "config", &testService{value: "tenant"}))
+	require.NoError(t, RegisterValue(child, "extra", &testService{value: "child-only"}))
+
+	cfg := Must[*testService](child, "config")
+	assert.Equal(t, "tenant", cfg.value)
+
+	cfg = Must[*testService](parent, "config")
+	assert.Equal(t, "base", cfg.value)
+
+	assert.False(t, parent.Has("extra"))
+	assert.ElementsMatch(t, []string{"config", "extra"}, child.Services())
+}
+
+func TestChild_NotFound(t *testing.T) {
+	child := NewChild(New())
+
+	_, err := child.Resolve("missing")
+	assert.ErrorIs(t, err, ErrServiceNotFound("missing"))
+	assert.False(t, child.Has("missing"))
+}
+
+func TestChild_StartStopOnlyManagesOwnServices(t *testing.T) {
+	parent := New()
+	parentSvc := &mockService{name: "parent"}
+	childSvc := &mockService{name: "child"}
+
+	require.NoError(t, parent.Register("parent", func(c Vessel) (any, error) {
+		return parentSvc, nil
+	}))
+
+	child := NewChild(parent)
+	require.NoError(t, child.Register("child", func(c Vessel) (any, error) {
+		return childSvc, nil
+	}))
+
+	ctx := context.Background()
+
+	require.NoError(t, child.Start(ctx))
+	assert.True(t, childSvc.started)
+	assert.False(t, parentSvc.started)
+	assert.False(t, child.IsStarted("parent"))
+
+	require.NoError(t, parent.Start(ctx))
+	assert.True(t, child.IsStarted("parent"))
+
+	require.NoError(t, child.Stop(ctx))
+	assert.True(t, childSvc.stopped)
+	assert.False(t, parentSvc.stopped)
+	assert.True(t, parent.IsStarted("parent"))
+}
+
+func TestChild_ScopeResolvesParentScopedServices(t *testing.T) {
+	parent := New()
+	require.NoError(t, RegisterScoped(parent, "session", func(c Vessel) (*testService, error) {
+		return &testService{value: "session"}, nil
+	}))
+
+	child := NewChild(parent)
+
+	s := child.BeginScope()
+	defer func() { _ = s.End() }()
+
+	first, err := ResolveScope[*testService](s, "session")
+	require.NoError(t, err)
+
+	second, err := ResolveScope[*testService](s, "session")
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+}
+
+func TestChild_ConstructorFallback(t *testing.T) {
+	parent := New()
+	require.NoError(t, ProvideConstructor(parent, newTestDatabase))
+	require.NoError(t, ProvideConstructor(parent, newTestLogger))
+
+	child := NewChild(parent)
+	require.NoError(t, ProvideConstructor(child, newTestUserService))
+
+	assert.True(t, HasType[*testDatabase](child))
+
+	svc, err := InjectType[*testUserService](child)
+	require.NoError(t, err)
+
+	db, err := InjectType[*testDatabase](parent)
+	require.NoError(t, err)
+	assert.Same(t, db, svc.db)
+
+	assert.False(t, HasType[*testUserService](parent))
+}
@@ -0,0 +1,165 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/xraph/go-utils/di"
+)
+
+// Close shuts the container down for good: it stops it, then releases every
+// created singleton. See (*containerImpl).Close.
+//
+// Example:
+//
+//	defer vessel.Close(context.Background(), c)
+func Close(ctx context.Context, c Vessel) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Close requires *containerImpl, got %T", c)
+	}
+
+	return impl.Close(ctx)
+}
+
+// Close stops the container and releases every created singleton.
+// Singletons that Resolve started on a container that was never started
+// are stopped too, in reverse dependency order, before any release.
+//
+// Name-based singletons are released in reverse dependency order, then
+// type-based singletons in reverse creation order. A singleton
+// implementing di.Disposable is disposed; otherwise one implementing
+// io.Closer is closed. Each release gets the same deadline as a Stop
+// (see WithStopTimeout). Cached instances and lifecycle hooks are
+// dropped either way, so nothing keeps the instances reachable and a later
+// Start rebuilds them from their factories.
+//
+// Every singleton is released even if others fail; the returned error
+// joins the Stop error and one error per failed release.
+func (c *containerImpl) Close(ctx context.Context) error {
+	c.mu.RLock()
+	running := c.started
+	c.mu.RUnlock()
+
+	closeErrs := []error{c.Stop(ctx)}
+
+	c.mu.RLock()
+	order, err := c.graph.TopologicalSort()
+	if err != nil {
+		// Fall back to registration order so everything is still released
+		order = append([]string(nil), c.graph.order...)
+	}
+	c.mu.RUnlock()
+
+	// Stop was a no-op, but Resolve may have auto-started singletons
+	if !running {
+		for i := len(order) - 1; i >= 0; i-- {
+			if err := c.stopService(ctx, order[i]); err != nil {
+				closeErrs = append(closeErrs, NewServiceError(order[i], "stop", err))
+			}
+		}
+	}
+
+	released := make(map[*serviceRegistration]bool, len(order))
+
+	for i := len(order) - 1; i >= 0; i-- {
+		c.mu.RLock()
+		reg, exists := c.services[order[i]]
+		c.mu.RUnlock()
+
+		// Module aliases share their target's registration
+		if !exists || released[reg] {
+			continue
+		}
+
+		released[reg] = true
+
+		reg.mu.Lock()
+		instance := reg.instance
+		reg.instance = nil
+		reg.started = false
+		reg.mu.Unlock()
+
+		if err := c.release(ctx, instance); err != nil {
+			closeErrs = append(closeErrs, NewServiceError(reg.name, "close", err))
+		}
+	}
+
+	for _, reg := range c.createdTypes() {
+		reg.mu.Lock()
+		instance := reg.instance
+		reg.instance = nil
+		reg.mu.Unlock()
+
+		if err := c.release(ctx, instance); err != nil {
+			closeErrs = append(closeErrs, NewServiceError(reg.key.String(), "close", err))
+		}
+	}
+
+	c.lifecycle.reset()
+
+	return errors.Join(closeErrs...)
+}
+
+// createdTypes returns the type-based registrations holding a singleton,
+// most recently created first.
+func (c *containerImpl) createdTypes() []*typeRegistration {
+	if c.typeRegistry == nil {
+		return nil
+	}
+
+	c.typeRegistry.mu.RLock()
+
+	seqs := make(map[*typeRegistration]uint64, len(c.typeRegistry.services))
+
+	for _, reg := range c.typeRegistry.services {
+		reg.mu.RLock()
+		if reg.instance != nil {
+			seqs[reg] = reg.createdSeq
+		}
+		reg.mu.RUnlock()
+	}
+
+	c.typeRegistry.mu.RUnlock()
+
+	// Aliases and As types share registrations, so the map also dedupes
+	created := make([]*typeRegistration, 0, len(seqs))
+	for reg := range seqs {
+		created = append(created, reg)
+	}
+
+	sort.Slice(created, func(i, j int) bool {
+		return seqs[created[i]] > seqs[created[j]]
+	})
+
+	return created
+}
+
+// release disposes or closes an instance within its stop deadline.
+func (c *containerImpl) release(ctx context.Context, instance any) error {
+	dispose := disposer(instance)
+	if dispose == nil {
+		return nil
+	}
+
+	stopCtx, cancel := c.stopContext(ctx)
+	defer cancel()
+
+	return stopWithDeadline(stopCtx, func(context.Context) error { return dispose() })
+}
+
+// disposer returns the function releasing instance: Dispose for a
+// di.Disposable, otherwise Close for an io.Closer, or nil for neither.
+func disposer(instance any) func() error {
+	switch v := instance.(type) {
+	case di.Disposable:
+		return v.Dispose
+	case io.Closer:
+		return v.Close
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCloser is an io.Closer that logs to events.
+type recordingCloser struct {
+	name   string
+	events *[]string
+	err    error
+}
+
+func (r *recordingCloser) Close() error {
+	*r.events = append(*r.events, "close "+r.name)
+
+	return r.err
+}
+
+// recordingDisposable is both a di.Disposable and an io.Closer.
+type recordingDisposable struct {
+	recordingCloser
+}
+
+func (r *recordingDisposable) Dispose() error {
+	*r.events = append(*r.events, "dispose "+r.name)
+
+	return r.err
+}
+
+func TestClose_ReleasesInReverseDependencyOrder(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	closer := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return &recordingCloser{name: name, events: &events}, nil
+		}
+	}
+
+	require.NoError(t, c.Register("api", func(c Vessel) (any, error) {
+		if _, err := c.Resolve("db"); err != nil {
+			return nil, err
+		}
+
+		return &recordingCloser{name: "api", events: &events}, nil
+	}, WithDependencies("db")))
+	require.NoError(t, c.Register("db", closer("db")))
+	require.NoError(t, c.Register("cache", func(c Vessel) (any, error) {
+		return &recordingDisposable{recordingCloser{name: "cache", events: &events}}, nil
+	}))
+	require.NoError(t, c.Register("unused", closer("unused")))
+	require.NoError(t, c.Register("temp", closer("temp"), Transient()))
+
+	for _, name := range []string{"api", "cache", "temp"} {
+		_, err := c.Resolve(name)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, Close(context.Background(), c))
+
+	// Never-created and transient services are skipped; Dispose wins over Close
+	assert.Equal(t, []string{"dispose cache", "close api", "close db"}, events)
+
+	impl := c.(*containerImpl)
+	for _, reg := range impl.services {
+		assert.Nil(t, reg.instance)
+	}
+}
+
+func TestClose_TypeBasedSingletons(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, ProvideConstructor(c, func() *recordingCloser {
+		return &recordingCloser{name: "db", events: &events}
+	}))
+	require.NoError(t, ProvideConstructor(c, func(db *recordingCloser) *recordingDisposable {
+		return &recordingDisposable{recordingCloser{name: "repo", events: &events}}
+	}))
+
+	_, err := InjectType[*recordingDisposable](c)
+	require.NoError(t, err)
+
+	require.NoError(t, c.(*containerImpl).Close(context.Background()))
+	assert.Equal(t, []string{"dispose repo", "close db"}, events)
+}
+
+func TestClose_JoinsErrorsAndAllowsRebuild(t *testing.T) {
+	c := New()
+	events := []string{}
+	builds := 0
+	closeErr := errors.New("close failed")
+
+	require.NoError(t, c.Register("a", func(c Vessel) (any, error) {
+		builds++
+
+		return &recordingCloser{name: "a", events: &events, err: closeErr}, nil
+	}))
+	require.NoError(t, c.Register("b", func(c Vessel) (any, error) {
+		return &recordingCloser{name: "b", events: &events}, nil
+	}, WithDependencies("a")))
+
+	require.NoError(t, c.Start(context.Background()))
+
+	err := Close(context.Background(), c)
+	assert.ErrorIs(t, err, closeErr)
+	assert.Equal(t, []string{"close b", "close a"}, events)
+
+	// A closed container rebuilds its singletons on the next Start
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, 2, builds)
+	assert.True(t, c.IsStarted("a"))
+}
+
+func TestClose_RunsStopFirst(t *testing.T) {
+	c := New()
+	svc := &mockService{name: "svc"}
+	require.NoError(t, RegisterValue(c, "svc", svc))
+	require.NoError(t, c.Start(context.Background()))
+
+	require.NoError(t, Close(context.Background(), c))
+	assert.True(t, svc.stopped)
+	assert.True(t, svc.disposed)
+}
+
+func TestClose_StopsAutoStartedSingletons(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	stopping := func(name string) *mockServiceWithCallback {
+		return &mockServiceWithCallback{
+			mockService: mockService{name: name},
+			onStop:      func() { events = append(events, "stop "+name) },
+		}
+	}
+
+	db := stopping("db")
+	require.NoError(t, RegisterValue(c, "db", db))
+	require.NoError(t, c.Register("api", func(c Vessel) (any, error) {
+		if _, err := c.Resolve("db"); err != nil {
+			return nil, err
+		}
+
+		return stopping("api"), nil
+	}, WithDependencies("db")))
+
+	// Never started, but Resolve auto-starts both
+	api := Must[*mockServiceWithCallback](c, "api")
+	require.True(t, api.started)
+
+	require.NoError(t, Close(context.Background(), c))
+	assert.Equal(t, []string{"stop api", "stop db"}, events)
+	assert.True(t, api.disposed)
+	assert.True(t, db.disposed)
+}
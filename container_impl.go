@@ -2,8 +2,12 @@ package vessel
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/xraph/go-utils/di"
 )
@@ -14,7 +18,13 @@ type containerImpl struct {
 	instances    map[string]any
 	graph        *DependencyGraph
 	middleware   *middlewareChain
-	typeRegistry *typeRegistry // Type-based registry for dig-like constructor injection
+	typeRegistry *typeRegistry  // Type-based registry for dig-like constructor injection
+	lifecycle    *lifecycle     // Hooks appended by factories, run by Start/Stop
+	monitor      *healthMonitor // Background health checks, nil unless enabled
+	scopes       *scopeTracker  // Open scopes, nil unless tracking is enabled
+	hasPrivate   atomic.Bool    // Some module service is private
+	options      containerOptions
+	parent       Vessel // Fallback for unregistered names (child containers)
 	started      bool
 	mu           sync.RWMutex
 }
@@ -23,30 +33,70 @@ type containerImpl struct {
 type serviceRegistration struct {
 	name         string
 	factory      Factory
+	ctxFactory   ContextFactory // Set when registered with RegisterContext
 	singleton    bool
 	scoped       bool
 	dependencies []string // Backward compat: just names
 	deps         []di.Dep // New: full dependency specs with modes
 	groups       []string
 	metadata     map[string]string
+	decorators   []serviceDecorator // Applied to each new instance, in order
+	private      string             // Module path the service is private to, guarded by the container lock
 	instance     any
 	started      bool
 	mu           sync.RWMutex
 }
 
 // newContainerImpl creates a new DI container implementation.
-func newContainerImpl() Vessel {
-	return &containerImpl{
+func newContainerImpl(opts ...ContainerOption) Vessel {
+	c := &containerImpl{
 		services:     make(map[string]*serviceRegistration),
 		instances:    make(map[string]any),
 		graph:        NewDependencyGraph(),
 		middleware:   newMiddlewareChain(),
 		typeRegistry: newTypeRegistry(),
+		lifecycle:    newLifecycle(),
+		options:      newContainerOptions(opts),
 	}
+
+	if c.options.scopeTracking {
+		c.scopes = newScopeTracker(c.options.scopeLeaks)
+	}
+
+	if c.options.healthMonitor != nil {
+		c.monitor = newHealthMonitor(*c.options.healthMonitor)
+	}
+
+	return c
 }
 
 // Register adds a service factory to the container.
 func (c *containerImpl) Register(name string, factory Factory, opts ...RegisterOption) error {
+	if factory == nil {
+		return ErrInvalidFactory
+	}
+
+	return c.register(name, factory, nil, opts, false)
+}
+
+// RegisterContext adds a context-receiving service factory to the container.
+// The factory gets the context of the resolution that creates the instance.
+func (c *containerImpl) RegisterContext(name string, factory ContextFactory, opts ...RegisterOption) error {
+	if factory == nil {
+		return ErrInvalidFactory
+	}
+
+	// Plain factory for callers that have no context to pass
+	plain := func(c Vessel) (any, error) {
+		return factory(context.Background(), c)
+	}
+
+	return c.register(name, plain, factory, opts, false)
+}
+
+// register adds a registration built from either factory flavour.
+// With replace set, an existing registration of the same name is overwritten.
+func (c *containerImpl) register(name string, factory Factory, ctxFactory ContextFactory, opts []RegisterOption, replace bool) error {
 	// Merge options
 	merged := mergeOptions(opts)
 
@@ -54,14 +104,19 @@ func (c *containerImpl) Register(name string, factory Factory, opts ...RegisterO
 		return fmt.Errorf("service name cannot be empty")
 	}
 
-	if factory == nil {
-		return ErrInvalidFactory
-	}
+	// Warnings are reported after the lock is released (defers run in
+	// reverse), so callbacks may use the container
+	var captive []CaptiveDependency
+	defer func() {
+		if len(captive) > 0 {
+			c.options.captive.warnCaptive(captive)
+		}
+	}()
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, exists := c.services[name]; exists {
+	if _, exists := c.services[name]; exists && !replace {
 		return ErrServiceAlreadyExists(name)
 	}
 
@@ -73,6 +128,7 @@ func (c *containerImpl) Register(name string, factory Factory, opts ...RegisterO
 	reg := &serviceRegistration{
 		name:         name,
 		factory:      factory,
+		ctxFactory:   ctxFactory,
 		singleton:    merged.Lifecycle == "singleton",
 		scoped:       merged.Lifecycle == "scoped",
 		dependencies: allDepNames,
@@ -81,6 +137,15 @@ func (c *containerImpl) Register(name string, factory Factory, opts ...RegisterO
 		metadata:     merged.Metadata,
 	}
 
+	switch c.options.captive.Mode {
+	case CaptiveError:
+		if found := c.findCaptive(reg); len(found) > 0 {
+			return ErrCaptiveDependency(found[0])
+		}
+	case CaptiveWarn:
+		captive = c.findCaptive(reg)
+	}
+
 	// Add to services map
 	c.services[name] = reg
 
@@ -99,15 +164,36 @@ func (c *containerImpl) Register(name string, factory Factory, opts ...RegisterO
 // started when first resolved. This enables Angular-like dependency injection where
 // dependencies are fully ready when resolved.
 func (c *containerImpl) Resolve(name string) (any, error) {
-	ctx := context.Background()
+	return c.ResolveContext(context.Background(), name)
+}
 
+// ResolveContext returns a service by name using ctx for the whole resolution.
+// The context reaches middleware, context factories and di.Service.Start
+// when the service is auto-started.
+func (c *containerImpl) ResolveContext(ctx context.Context, name string) (any, error) {
+	return c.resolveVisible(ctx, name, nil)
+}
+
+// resolveVisible resolves name like resolveFrom, unless it is a module's
+// private service and ctx doesn't come from that module.
+func (c *containerImpl) resolveVisible(ctx context.Context, name string, from *construction) (any, error) {
+	if c.hidden(ctx, name) {
+		return nil, ErrServiceNotFound(name)
+	}
+
+	return c.resolveFrom(outsideModule(ctx), name, from)
+}
+
+// resolveFrom resolves name on behalf of from, the construction whose
+// factory asked for it, or nil when resolving from outside a factory.
+func (c *containerImpl) resolveFrom(ctx context.Context, name string, from *construction) (any, error) {
 	// Call middleware before resolve
 	if err := c.middleware.beforeResolve(ctx, name); err != nil {
 		return nil, err
 	}
 
 	// Perform actual resolution
-	service, err := c.resolveInternal(name)
+	service, err := c.resolveInternal(ctx, name, from)
 
 	// Call middleware after resolve
 	if mwErr := c.middleware.afterResolve(ctx, name, service, err); mwErr != nil {
@@ -118,15 +204,25 @@ func (c *containerImpl) Resolve(name string) (any, error) {
 }
 
 // resolveInternal performs the actual service resolution without middleware.
-func (c *containerImpl) resolveInternal(name string) (any, error) {
+func (c *containerImpl) resolveInternal(ctx context.Context, name string, from *construction) (any, error) {
 	c.mu.RLock()
 	reg, exists := c.services[name]
 	c.mu.RUnlock()
 
 	if !exists {
+		if c.parent != nil {
+			return resolveContext(ctx, c.parent, name)
+		}
+
 		return nil, ErrServiceNotFound(name)
 	}
 
+	// Resolving a service this chain is still building would deadlock on
+	// its lock, or recurse forever for a transient
+	if cycle := from.cycle(reg.name); cycle != nil {
+		return nil, ErrCircularDependency(cycle)
+	}
+
 	// Singleton: return cached instance
 	if reg.singleton {
 		// Fast path: check if already created AND started (read lock)
@@ -155,7 +251,9 @@ func (c *containerImpl) resolveInternal(name string) (any, error) {
 		if reg.instance == nil {
 			// Call factory while holding lock (container lock is separate, so no deadlock)
 			// Note: factory may call c.Resolve() which uses c.mu (different lock)
-			instance, err := reg.factory(c)
+			instance, err := c.build(reg.name, reg.name, from, func(v Vessel) (any, error) {
+				return reg.create(ctx, v)
+			})
 			if err != nil {
 				return nil, NewServiceError(name, "resolve", err)
 			}
@@ -166,24 +264,8 @@ func (c *containerImpl) resolveInternal(name string) (any, error) {
 
 		// Auto-start if service implements di.Service and not yet started
 		if !reg.started {
-			if svc, ok := existingInstance.(di.Service); ok {
-				ctx := context.Background()
-
-				// Call middleware before start
-				if err := c.middleware.beforeStart(ctx, name); err != nil {
-					return nil, err
-				}
-
-				startErr := svc.Start(ctx)
-
-				// Call middleware after start
-				if mwErr := c.middleware.afterStart(ctx, name, startErr); mwErr != nil {
-					return nil, mwErr
-				}
-
-				if startErr != nil {
-					return nil, NewServiceError(name, "auto_start", startErr)
-				}
+			if err := c.autoStart(ctx, name, existingInstance); err != nil {
+				return nil, err
 			}
 
 			reg.started = true
@@ -197,34 +279,48 @@ func (c *containerImpl) resolveInternal(name string) (any, error) {
 		return nil, fmt.Errorf("scoped service %s must be resolved from a scope", name)
 	}
 
-	// Transient: create new instance each time
-	instance, err := reg.factory(c)
+	// Transient: create new instance each time. Transients are never
+	// started, so their hooks go to the service that resolved them.
+	instance, err := c.build(reg.name, from.hookOwner(), from, func(v Vessel) (any, error) {
+		return reg.create(ctx, v)
+	})
 	if err != nil {
 		return nil, NewServiceError(name, "resolve", err)
 	}
 
 	// Auto-start transient services that implement di.Service
-	if svc, ok := instance.(di.Service); ok {
-		ctx := context.Background()
+	if err := c.autoStart(ctx, name, instance); err != nil {
+		return nil, err
+	}
 
-		// Call middleware before start
-		if err := c.middleware.beforeStart(ctx, name); err != nil {
-			return nil, err
-		}
+	return instance, nil
+}
 
-		startErr := svc.Start(ctx)
+// autoStart starts instance if it implements di.Service, running the
+// BeforeStart and AfterStart middleware around it.
+func (c *containerImpl) autoStart(ctx context.Context, name string, instance any) error {
+	svc, ok := instance.(di.Service)
+	if !ok {
+		return nil
+	}
 
-		// Call middleware after start
-		if mwErr := c.middleware.afterStart(ctx, name, startErr); mwErr != nil {
-			return nil, mwErr
-		}
+	// Call middleware before start
+	if err := c.middleware.beforeStart(ctx, name); err != nil {
+		return err
+	}
 
-		if startErr != nil {
-			return nil, NewServiceError(name, "auto_start", startErr)
-		}
+	startErr := svc.Start(ctx)
+
+	// Call middleware after start
+	if mwErr := c.middleware.afterStart(ctx, name, startErr); mwErr != nil {
+		return mwErr
 	}
 
-	return instance, nil
+	if startErr != nil {
+		return NewServiceError(name, "auto_start", startErr)
+	}
+
+	return nil
 }
 
 // Use adds middleware to the container.
@@ -235,12 +331,21 @@ func (c *containerImpl) Use(middleware Middleware) {
 	c.middleware.add(middleware)
 }
 
-// Has checks if a service is registered.
+// Has checks if a service is registered here or in a parent container.
+// A module's private services are only visible from that module.
 func (c *containerImpl) Has(name string) bool {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
+	reg, exists := c.services[name]
+	if exists && reg.private != "" {
+		c.mu.RUnlock()
 
-	_, exists := c.services[name]
+		return false
+	}
+	c.mu.RUnlock()
+
+	if !exists && c.parent != nil {
+		return c.parent.Has(name)
+	}
 
 	return exists
 }
@@ -253,6 +358,10 @@ func (c *containerImpl) IsStarted(name string) bool {
 	c.mu.RUnlock()
 
 	if !exists {
+		if c.parent != nil {
+			return c.parent.IsStarted(name)
+		}
+
 		return false
 	}
 
@@ -271,6 +380,14 @@ func (c *containerImpl) ResolveReady(ctx context.Context, name string) (any, err
 	c.mu.RUnlock()
 
 	if !exists {
+		if c.parent != nil {
+			return c.parent.ResolveReady(ctx, name)
+		}
+
+		return nil, ErrServiceNotFound(name)
+	}
+
+	if c.hidden(ctx, name) {
 		return nil, ErrServiceNotFound(name)
 	}
 
@@ -287,17 +404,28 @@ func (c *containerImpl) ResolveReady(ctx context.Context, name string) (any, err
 	}
 
 	// Now resolve the service
-	return c.Resolve(name)
+	return c.ResolveContext(ctx, name)
 }
 
-// Services returns all registered service names.
+// Services returns all registered service names, including those
+// inherited from a parent container, except modules' private services.
 func (c *containerImpl) Services() []string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	names := make([]string, 0, len(c.services))
-	for name := range c.services {
-		names = append(names, name)
+	for name, reg := range c.services {
+		if reg.private == "" {
+			names = append(names, name)
+		}
+	}
+
+	if c.parent != nil {
+		for _, name := range c.parent.Services() {
+			if _, shadowed := c.services[name]; !shadowed {
+				names = append(names, name)
+			}
+		}
 	}
 
 	return names
@@ -305,7 +433,10 @@ func (c *containerImpl) Services() []string {
 
 // BeginScope creates a new scope for request-scoped services.
 func (c *containerImpl) BeginScope() Scope {
-	return newScope(c)
+	s := newScope(c)
+	c.scopes.track(s)
+
+	return s
 }
 
 // Start initializes all services in dependency order.
@@ -329,27 +460,65 @@ func (c *containerImpl) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Snapshot each service's registered dependencies for the parallel scheduler
+	var deps map[string][]string
+	if c.options.parallelStart {
+		deps = c.graph.knownDependencies(order)
+	}
+
 	c.mu.Unlock()
 
-	// Start services in order (without holding container lock)
-	// Services that are already started (via auto-start on Resolve) will be skipped
-	for _, name := range order {
-		if err := c.startService(ctx, name); err != nil {
-			// Rollback: stop already started services
-			c.stopServices(ctx, order)
+	// Hooks of type-based singletons created so far come first
+	if err := c.startHooks(ctx, ""); err != nil {
+		_ = c.stopHooks(ctx, detached)
+
+		return err
+	}
+
+	if c.options.parallelStart {
+		if err := c.startParallel(ctx, order, deps); err != nil {
+			_ = c.stopHooks(ctx, detached)
 
-			return NewServiceError(name, "start", err)
+			return err
+		}
+	} else {
+		// Start services in order (without holding container lock)
+		// Services that are already started (via auto-start on Resolve) will be skipped
+		for _, name := range order {
+			if err := c.startService(ctx, name); err != nil {
+				// Rollback: stop already started services
+				c.stopServices(ctx, order)
+				_ = c.stopHooks(ctx, detached)
+
+				return NewServiceError(name, "start", err)
+			}
 		}
 	}
 
+	// Then those of type-based singletons a service created after starting
+	if err := c.startHooks(ctx, ""); err != nil {
+		c.stopServices(ctx, order)
+		_ = c.stopHooks(ctx, detached)
+
+		return err
+	}
+
 	c.mu.Lock()
 	c.started = true
 	c.mu.Unlock()
 
+	if c.monitor != nil {
+		c.monitor.start(c)
+	}
+
+	c.scopes.start()
+
 	return nil
 }
 
 // Stop shuts down all services in reverse order.
+// Every started service is stopped even if others fail; the returned error
+// joins one service error per failure.
 func (c *containerImpl) Stop(ctx context.Context) error {
 	c.mu.Lock()
 
@@ -369,37 +538,47 @@ func (c *containerImpl) Stop(ctx context.Context) error {
 
 	c.mu.Unlock()
 
+	if c.monitor != nil {
+		c.monitor.stop()
+	}
+
+	c.scopes.stop()
+
 	// Stop in reverse order (without holding container lock)
+	var stopErrs []error
+
 	for i := len(order) - 1; i >= 0; i-- {
 		name := order[i]
-		if err := c.stopService(ctx, name); err != nil {
+
+		serviceCtx, cancel := c.stopBudget(ctx, order[:i+1])
+		err := c.stopService(serviceCtx, name)
+		cancel()
+
+		if err != nil {
 			// Continue stopping other services, but collect error
-			return NewServiceError(name, "stop", err)
+			stopErrs = append(stopErrs, NewServiceError(name, "stop", err))
 		}
 	}
 
+	// Type-based singletons outlive the services they were created for
+	if err := c.stopHooks(ctx, detached); err != nil {
+		stopErrs = append(stopErrs, err)
+	}
+
 	c.mu.Lock()
 	c.started = false
 	c.mu.Unlock()
 
-	return nil
+	return errors.Join(stopErrs...)
 }
 
-// Health checks all services.
+// Health checks all instantiated singletons and returns the first failing
+// critical service in name order. Failures of services registered with
+// NonCritical are left to HealthReport.
 func (c *containerImpl) Health(ctx context.Context) error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	for name, reg := range c.services {
-		// Only check singleton services that have been instantiated
-		if !reg.singleton || reg.instance == nil {
-			continue
-		}
-
-		if checker, ok := reg.instance.(di.HealthChecker); ok {
-			if err := checker.Health(ctx); err != nil {
-				return NewServiceError(name, "health", err)
-			}
+	for _, result := range c.HealthReport(ctx).Services {
+		if result.Err != nil && result.Critical {
+			return NewServiceError(result.Name, "health", result.Err)
 		}
 	}
 
@@ -407,35 +586,60 @@ func (c *containerImpl) Health(ctx context.Context) error {
 }
 
 // Inspect returns diagnostic information about a service.
+// Health comes from the health monitor's cache when one is running;
+// otherwise the service's health check runs without holding any lock.
 func (c *containerImpl) Inspect(name string) ServiceInfo {
+	info, reg, exists := c.inspect(name)
+	if !exists {
+		if c.parent != nil {
+			return c.parent.Inspect(name)
+		}
+
+		return info
+	}
+
+	reg.mu.RLock()
+	instance := reg.instance
+	reg.mu.RUnlock()
+
+	if checker, ok := instance.(di.HealthChecker); ok {
+		if status, cached := c.cachedHealth(reg.name); cached {
+			info.Healthy = status == HealthHealthy
+		} else {
+			info.Healthy = checker.Health(context.Background()) == nil
+		}
+	}
+
+	return info
+}
+
+// cachedHealth returns the health monitor's status for a service.
+func (c *containerImpl) cachedHealth(name string) (HealthStatus, bool) {
+	if c.monitor == nil {
+		return "", false
+	}
+
+	return c.monitor.status(name)
+}
+
+// inspect collects everything Inspect reports except health.
+func (c *containerImpl) inspect(name string) (ServiceInfo, *serviceRegistration, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	reg, exists := c.services[name]
 	if !exists {
-		return ServiceInfo{Name: name}
+		return ServiceInfo{Name: name}, nil, false
 	}
 
 	reg.mu.RLock()
 	defer reg.mu.RUnlock()
 
-	lifecycle := "transient"
-	if reg.singleton {
-		lifecycle = "singleton"
-	} else if reg.scoped {
-		lifecycle = "scoped"
-	}
-
 	typeName := "unknown"
 	if reg.instance != nil {
 		typeName = fmt.Sprintf("%T", reg.instance)
 	}
 
-	healthy := false
-	if checker, ok := reg.instance.(di.HealthChecker); ok {
-		healthy = checker.Health(context.Background()) == nil
-	}
-
 	// Copy metadata and add groups
 	metadata := make(map[string]string)
 	for k, v := range reg.metadata {
@@ -448,16 +652,51 @@ func (c *containerImpl) Inspect(name string) ServiceInfo {
 		metadata["__groups"] = joinStrings(reg.groups, ",")
 	}
 
+	if len(reg.decorators) > 0 {
+		metadata["__decorators"] = strconv.Itoa(len(reg.decorators))
+	}
+
 	return ServiceInfo{
 		Name:         name,
 		Type:         typeName,
-		Lifecycle:    lifecycle,
+		Lifecycle:    reg.lifecycle(),
 		Dependencies: reg.dependencies,
 		Deps:         reg.deps,
 		Started:      reg.started,
-		Healthy:      healthy,
 		Metadata:     metadata,
+	}, reg, true
+}
+
+// create builds a new instance, passing ctx to context factories, and runs
+// it through the registration's decorators.
+// A context that is already done aborts before the factory runs.
+func (reg *serviceRegistration) create(ctx context.Context, c Vessel) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+
+	var (
+		instance any
+		err      error
+	)
+
+	if reg.ctxFactory != nil {
+		instance, err = reg.ctxFactory(ctx, c)
+	} else {
+		instance, err = reg.factory(c)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, decorate := range reg.decorators {
+		if instance, err = decorate(c, instance); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
 }
 
 // startService starts a single service.
@@ -472,28 +711,42 @@ func (c *containerImpl) startService(ctx context.Context, name string) error {
 		return nil // Service not registered, skip
 	}
 
+	// A module alias starts the service it points to
+	name = reg.name
+
 	// Check if already started
 	reg.mu.RLock()
 	started := reg.started
 	reg.mu.RUnlock()
 
-	if started {
-		return nil // Already started (via auto-start on Resolve), skip
-	}
-
-	// Resolve the service instance (creates and auto-starts if needed)
-	// Since Resolve() now auto-starts services, this should handle everything
-	_, err := c.Resolve(name)
-	if err != nil {
-		return err
+	if !started {
+		// Resolve the service instance (creates and auto-starts if needed)
+		// Since Resolve() now auto-starts services, this should handle everything
+		if _, err := c.resolveFrom(ctx, name, nil); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	// Run lifecycle hooks appended since the previous service started,
+	// including those from an earlier auto-start on Resolve
+	return c.startHooks(ctx, name)
 }
 
-// stopService stops a single service.
+// stopService stops a single service within its own stop deadline.
 func (c *containerImpl) stopService(ctx context.Context, name string) error {
-	reg := c.services[name]
+	c.mu.RLock()
+	reg, exists := c.services[name]
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	// A module alias stops the service it points to, hooks first
+	name = reg.name
+
+	// Hooks are stopped before the service they were created with
+	hookErr := c.stopHooks(ctx, ownedBy(name))
 
 	reg.mu.RLock()
 	instance := reg.instance
@@ -501,13 +754,16 @@ func (c *containerImpl) stopService(ctx context.Context, name string) error {
 	reg.mu.RUnlock()
 
 	if !started || instance == nil {
-		return nil
+		return hookErr
 	}
 
 	// Call Stop if service implements Service interface
 	if svc, ok := instance.(di.Service); ok {
-		if err := svc.Stop(ctx); err != nil {
-			return err
+		stopCtx, cancel := c.stopContext(ctx)
+		defer cancel()
+
+		if err := stopWithDeadline(stopCtx, svc.Stop); err != nil {
+			return errors.Join(hookErr, err)
 		}
 
 		reg.mu.Lock()
@@ -515,7 +771,98 @@ func (c *containerImpl) stopService(ctx context.Context, name string) error {
 		reg.mu.Unlock()
 	}
 
-	return nil
+	return hookErr
+}
+
+// stopBudget derives the context for stopping the first service of
+// pending, listed in reverse stop order, when ctx has a deadline and
+// WithStopTimeout is not set: it gets an equal share of the time left among
+// the started services still to stop, so a hung service cannot use up the
+// deadline of the whole shutdown. Time a service doesn't use is shared by
+// the next ones.
+func (c *containerImpl) stopBudget(ctx context.Context, pending []string) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || c.options.stopTimeout > 0 {
+		return ctx, func() {}
+	}
+
+	started := 0
+
+	c.mu.RLock()
+
+	for _, name := range pending {
+		if reg, exists := c.services[name]; exists {
+			reg.mu.RLock()
+			if reg.started {
+				started++
+			}
+			reg.mu.RUnlock()
+		}
+	}
+
+	c.mu.RUnlock()
+
+	if started <= 1 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(started))
+}
+
+// stopContext derives the context for stopping a single service.
+func (c *containerImpl) stopContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.options.stopTimeout > 0 {
+		return context.WithTimeout(ctx, c.options.stopTimeout)
+	}
+
+	return ctx, func() {}
+}
+
+// stopWithDeadline calls stop and gives up once ctx is done, so a Stop
+// that ignores its context cannot block the rest of shutdown. Once ctx is
+// done, stop is not called at all: the service is reported as not
+// stopped rather than left stopping in the background.
+func stopWithDeadline(ctx context.Context, stop func(context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not stopped: %w", err)
+	}
+
+	err := callWithDeadline(ctx, stop)
+	if err == errAbandoned {
+		return fmt.Errorf("stop abandoned: %w", ctx.Err())
+	}
+
+	return err
+}
+
+// errAbandoned is returned by callWithDeadline when ctx ends first.
+var errAbandoned = errors.New("call abandoned")
+
+// callWithDeadline calls fn and returns errAbandoned once ctx is done,
+// leaving fn to finish in the background.
+func callWithDeadline(ctx context.Context, fn func(context.Context) error) error {
+	if ctx.Done() == nil {
+		return fn(ctx)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Prefer the real result if fn finished at the same moment
+		select {
+		case err := <-done:
+			return err
+		default:
+			return errAbandoned
+		}
+	}
 }
 
 // stopServices stops multiple services (for rollback).
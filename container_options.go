@@ -0,0 +1,64 @@
+package vessel
+
+import "time"
+
+// ContainerOption configures a container at construction time.
+type ContainerOption func(*containerOptions)
+
+// containerOptions holds container-wide configuration.
+type containerOptions struct {
+	parallelStart bool // Start independent services concurrently
+	startWorkers  int  // Max concurrent starts (0 = unlimited)
+
+	stopTimeout time.Duration // Deadline for each service's Stop (0 = ctx only)
+
+	captive CaptivePolicy // Lifetime mismatch detection
+
+	healthTimeout time.Duration         // Deadline for each health check (0 = DefaultHealthTimeout)
+	healthMonitor *HealthMonitorOptions // Background health checks (nil = disabled)
+
+	scopeTracking bool              // Record open scopes for ActiveScopes
+	scopeLeaks    *ScopeLeakOptions // Scope leak detection (nil = disabled)
+}
+
+// newContainerOptions applies opts on top of the defaults.
+func newContainerOptions(opts []ContainerOption) containerOptions {
+	var options containerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WithParallelStart makes Start launch every service whose dependencies are
+// already running at the same time, instead of one after another.
+// maxWorkers caps the number of services starting at once; zero or a
+// negative value means no limit.
+//
+// Dependencies are still started before their dependents, and a failure
+// stops everything that was started, exactly as with sequential start.
+//
+// Example:
+//
+//	c := vessel.New(vessel.WithParallelStart(8))
+func WithParallelStart(maxWorkers int) ContainerOption {
+	return func(o *containerOptions) {
+		o.parallelStart = true
+		o.startWorkers = maxWorkers
+	}
+}
+
+// WithStopTimeout gives every service its own deadline when the container
+// stops. A service whose Stop does not return in time is reported as failed
+// and shutdown moves on to the next service. The deadline never extends
+// past the one already set on the context passed to Stop.
+//
+// Example:
+//
+//	c := vessel.New(vessel.WithStopTimeout(5 * time.Second))
+func WithStopTimeout(timeout time.Duration) ContainerOption {
+	return func(o *containerOptions) {
+		o.stopTimeout = timeout
+	}
+}
@@ -467,6 +467,150 @@ func TestStop_ReverseOrder(t *testing.T) {
 	mu.Unlock()
 }
 
+func TestStop_ContinuesAfterErrors(t *testing.T) {
+	c := New()
+	base := &mockService{name: "base"}
+	middle := &mockService{name: "middle", stopErr: errors.New("middle stuck")}
+	top := &mockService{name: "top", stopErr: errors.New("top stuck")}
+
+	require.NoError(t, c.Register("base", func(c Vessel) (any, error) {
+		return base, nil
+	}))
+	require.NoError(t, c.Register("middle", func(c Vessel) (any, error) {
+		return middle, nil
+	}, WithDependencies("base")))
+	require.NoError(t, c.Register("top", func(c Vessel) (any, error) {
+		return top, nil
+	}, WithDependencies("middle")))
+
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+
+	err := c.Stop(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, middle.stopErr)
+	assert.ErrorIs(t, err, top.stopErr)
+	assert.Contains(t, err.Error(), "middle")
+	assert.Contains(t, err.Error(), "top")
+
+	// Services after the failures are still stopped
+	assert.True(t, base.stopped)
+	assert.False(t, c.IsStarted("base"))
+
+	// Container is no longer marked as started, so Start runs again
+	impl := c.(*containerImpl)
+	assert.False(t, impl.started)
+}
+
+func TestStop_PerServiceTimeout(t *testing.T) {
+	c := New(WithStopTimeout(20 * time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+
+	base := &mockService{name: "base"}
+
+	require.NoError(t, c.Register("base", func(c Vessel) (any, error) {
+		return base, nil
+	}))
+	require.NoError(t, c.Register("hung", func(c Vessel) (any, error) {
+		return &mockServiceWithCallback{
+			mockService: mockService{name: "hung"},
+			// Ignores its context entirely
+			onStop: func() { <-release },
+		}, nil
+	}, WithDependencies("base")))
+
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+
+	begin := time.Now()
+	err := c.Stop(ctx)
+
+	assert.Less(t, time.Since(begin), time.Second)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, base.stopped, "a hung service must not block the rest of shutdown")
+	assert.True(t, c.IsStarted("hung"))
+}
+
+func TestStop_ContextDeadline(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	defer close(release)
+
+	require.NoError(t, c.Register("hung", func(c Vessel) (any, error) {
+		return &mockServiceWithCallback{
+			mockService: mockService{name: "hung"},
+			onStop:      func() { <-release },
+		}, nil
+	}))
+
+	require.NoError(t, c.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.Stop(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStop_SharesContextDeadline(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	defer close(release)
+
+	base := &mockService{name: "base"}
+	mid := &mockService{name: "mid"}
+
+	require.NoError(t, RegisterValue(c, "base", base))
+	require.NoError(t, c.Register("mid", func(c Vessel) (any, error) {
+		return mid, nil
+	}, WithDependencies("base")))
+	require.NoError(t, c.Register("hung", func(c Vessel) (any, error) {
+		return &mockServiceWithCallback{
+			mockService: mockService{name: "hung"},
+			onStop:      func() { <-release },
+		}, nil
+	}, WithDependencies("mid")))
+
+	require.NoError(t, c.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	err := c.Stop(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "hung")
+
+	// The hung service only used its share, leaving time for the rest
+	assert.NotContains(t, err.Error(), "mid")
+	assert.NotContains(t, err.Error(), "base")
+	assert.True(t, mid.stopped)
+	assert.True(t, base.stopped)
+	assert.False(t, c.IsStarted("mid"))
+	assert.False(t, c.IsStarted("base"))
+}
+
+func TestStop_ExpiredContextSkipsStop(t *testing.T) {
+	c := New()
+	svc := &mockService{name: "svc"}
+	require.NoError(t, RegisterValue(c, "svc", svc))
+	require.NoError(t, c.Start(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Stop(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "not stopped")
+
+	// Stop was never called, so the service is still running
+	assert.False(t, svc.stopped)
+	assert.True(t, c.IsStarted("svc"))
+}
+
 func TestHealth_Success(t *testing.T) {
 	c := New()
 	svc := &mockService{name: "test", healthy: true}
@@ -1000,3 +1144,109 @@ func TestResolve_WithDependencies_AutoStartsInOrder(t *testing.T) {
 
 	assert.Less(t, depIdx, mainIdx, "Dependency should start before dependent")
 }
+
+type testCtxKey string
+
+// ctxCapturingService records the context it was started with.
+type ctxCapturingService struct {
+	mockService
+
+	startCtx context.Context
+}
+
+func (s *ctxCapturingService) Start(ctx context.Context) error {
+	s.startCtx = ctx
+
+	return s.mockService.Start(ctx)
+}
+
+func TestResolveContext_ReachesFactoryMiddlewareAndStart(t *testing.T) {
+	c := New().(*containerImpl)
+	svc := &ctxCapturingService{mockService: mockService{name: "svc"}}
+
+	var factoryValue, middlewareValue any
+
+	c.Use(&FuncMiddleware{
+		BeforeResolveFunc: func(ctx context.Context, name string) error {
+			middlewareValue = ctx.Value(testCtxKey("trace"))
+
+			return nil
+		},
+	})
+
+	err := RegisterContext(c, "svc", func(ctx context.Context, c Vessel) (any, error) {
+		factoryValue = ctx.Value(testCtxKey("trace"))
+
+		return svc, nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), testCtxKey("trace"), "abc")
+
+	instance, err := c.ResolveContext(ctx, "svc")
+	require.NoError(t, err)
+	assert.Same(t, svc, instance)
+
+	assert.Equal(t, "abc", factoryValue)
+	assert.Equal(t, "abc", middlewareValue)
+	require.NotNil(t, svc.startCtx)
+	assert.Equal(t, "abc", svc.startCtx.Value(testCtxKey("trace")))
+}
+
+func TestResolveContext_CancelledBeforeCreate(t *testing.T) {
+	c := New()
+	called := false
+
+	err := c.Register("svc", func(c Vessel) (any, error) {
+		called = true
+
+		return &mockService{name: "svc"}, nil
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.(*containerImpl).ResolveContext(ctx, "svc")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, called)
+
+	// A later resolution with a live context still works
+	_, err = c.Resolve("svc")
+	assert.NoError(t, err)
+}
+
+func TestStart_PassesContextToFactories(t *testing.T) {
+	c := New()
+
+	var seen any
+
+	err := RegisterContext(c, "svc", func(ctx context.Context, c Vessel) (any, error) {
+		seen = ctx.Value(testCtxKey("phase"))
+
+		return &mockService{name: "svc"}, nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), testCtxKey("phase"), "boot")
+	require.NoError(t, c.Start(ctx))
+	assert.Equal(t, "boot", seen)
+}
+
+func TestRegisterContext_PlainResolveUsesBackground(t *testing.T) {
+	c := New()
+
+	err := RegisterContext(c, "svc", func(ctx context.Context, c Vessel) (any, error) {
+		require.NotNil(t, ctx)
+
+		return "value", nil
+	})
+	require.NoError(t, err)
+
+	value, err := c.Resolve("svc")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	assert.ErrorIs(t, RegisterContext(c, "nil", nil), ErrInvalidFactory)
+}
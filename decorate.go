@@ -0,0 +1,214 @@
+package vessel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// serviceDecorator wraps an instance right after its factory creates it.
+type serviceDecorator func(c Vessel, instance any) (any, error)
+
+// Decorate wraps every new instance of a name-based service.
+// The decorator runs after the factory, once per created instance, so
+// singletons are decorated once and scoped services once per scope.
+// Several decorators on the same service apply in registration order,
+// each receiving the previous one's result.
+//
+// Decorators must be added before the service is first resolved.
+//
+// Example:
+//
+//	vessel.Decorate(c, "userRepo", func(c vessel.Vessel, repo UserRepo) (UserRepo, error) {
+//	    return NewCachedUserRepo(repo), nil
+//	})
+func Decorate[T any](c Vessel, name string, decorator func(Vessel, T) (T, error)) error {
+	if decorator == nil {
+		return errors.New("decorator cannot be nil")
+	}
+
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Decorate requires *containerImpl, got %T", c)
+	}
+
+	return impl.decorate(name, func(c Vessel, instance any) (any, error) {
+		typed, ok := instance.(T)
+		if !ok {
+			return nil, ErrTypeMismatch(name, instance)
+		}
+
+		return decorator(c, typed)
+	})
+}
+
+// decorate swaps the named registration for a copy carrying one more
+// decorator. Copying keeps registrations immutable, so Snapshot/Restore
+// also reverts decorators. Every name sharing the registration, such as a
+// module export and its qualified name, gets the copy.
+func (c *containerImpl) decorate(name string, decorator serviceDecorator) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reg, exists := c.services[name]
+	if !exists {
+		return ErrServiceNotFound(name)
+	}
+
+	reg.mu.RLock()
+	created := reg.instance != nil
+	reg.mu.RUnlock()
+
+	if created {
+		return NewServiceError(name, "decorate", errors.New("service already instantiated"))
+	}
+
+	decorated := reg.withDecorator(decorator)
+
+	for key, current := range c.services {
+		if current == reg {
+			c.services[key] = decorated
+		}
+	}
+
+	return nil
+}
+
+// withDecorator returns a copy of the registration with decorator appended.
+// It has no instance yet. The caller must hold the container lock.
+func (reg *serviceRegistration) withDecorator(decorator serviceDecorator) *serviceRegistration {
+	return &serviceRegistration{
+		name:         reg.name,
+		factory:      reg.factory,
+		ctxFactory:   reg.ctxFactory,
+		singleton:    reg.singleton,
+		scoped:       reg.scoped,
+		dependencies: reg.dependencies,
+		deps:         reg.deps,
+		groups:       reg.groups,
+		metadata:     reg.metadata,
+		decorators:   append(reg.decorators[:len(reg.decorators):len(reg.decorators)], decorator),
+		private:      reg.private,
+	}
+}
+
+// DecorateType wraps every new instance of a type-based service registered
+// with ProvideConstructor. The decorator is a function whose single result
+// (plus an optional error) is the decorated type; one of its parameters must
+// be that same type and receives the undecorated instance. Other parameters,
+// including In structs, are resolved from the container like constructor
+// parameters. Use WithName to decorate a named registration.
+//
+// Example:
+//
+//	vessel.DecorateType(c, func(db *Database, log *Logger) *Database {
+//	    return db.WithQueryLogging(log)
+//	})
+func DecorateType(c Vessel, decorator any, opts ...ConstructorOption) error {
+	info, err := analyzeConstructor(decorator)
+	if err != nil {
+		return fmt.Errorf("invalid decorator: %w", err)
+	}
+
+	if len(info.results) != 1 || info.results[0].isOut {
+		return errors.New("invalid decorator: must return exactly one value and an optional error")
+	}
+
+	target := info.results[0].typ
+
+	decorated := -1
+	for i, param := range info.params {
+		if !param.isIn && param.typ == target {
+			decorated = i
+
+			break
+		}
+	}
+
+	if decorated < 0 {
+		return fmt.Errorf("invalid decorator: must accept the decorated type %s", target)
+	}
+
+	config := &constructorConfig{}
+	for _, opt := range opts {
+		opt.applyConstructor(config)
+	}
+
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("DecorateType requires *containerImpl, got %T", c)
+	}
+
+	key := typeKey{typ: target, name: config.name}
+
+	reg, ok := impl.typeRegistry.get(key)
+	if !ok {
+		return fmt.Errorf("no service registered for type %s", key)
+	}
+
+	reg.mu.RLock()
+	created := reg.instance != nil
+	reg.mu.RUnlock()
+
+	if created {
+		return fmt.Errorf("cannot decorate type %s: already instantiated", key)
+	}
+
+	impl.typeRegistry.swap(reg, reg.withDecorator(func(container Vessel, instance any) (any, error) {
+		args := make([]reflect.Value, len(info.params))
+
+		for i, param := range info.params {
+			switch {
+			case i == decorated:
+				args[i] = reflectValue(instance, param.typ)
+			case param.isIn:
+				inValue, err := resolveInStruct(param, impl, container)
+				if err != nil {
+					return nil, err
+				}
+				args[i] = inValue
+			default:
+				resolved, err := resolveParam(param, impl, container)
+				if err != nil {
+					return nil, err
+				}
+				args[i] = reflectValue(resolved, param.typ)
+			}
+		}
+
+		results := info.fn.Call(args)
+
+		if info.hasError {
+			if errResult := results[len(results)-1]; !errResult.IsNil() {
+				return nil, errResult.Interface().(error)
+			}
+		}
+
+		return results[0].Interface(), nil
+	}))
+
+	return nil
+}
+
+// withDecorator returns a copy of the registration with decorator appended.
+func (reg *typeRegistration) withDecorator(decorator serviceDecorator) *typeRegistration {
+	return &typeRegistration{
+		key:         reg.key,
+		constructor: reg.constructor,
+		factory:     reg.factory,
+		lifecycle:   reg.lifecycle,
+		groups:      reg.groups,
+		container:   reg.container,
+		decorators:  append(reg.decorators[:len(reg.decorators):len(reg.decorators)], decorator),
+	}
+}
+
+// reflectValue wraps v for a reflective call, using the zero value of typ
+// when v is nil.
+func reflectValue(v any, typ reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(typ)
+	}
+
+	return reflect.ValueOf(v)
+}
@@ -0,0 +1,200 @@
+package vessel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testGreeter interface {
+	Greet() string
+}
+
+type plainGreeter struct{}
+
+func (plainGreeter) Greet() string { return "hello" }
+
+type wrappedGreeter struct {
+	inner  testGreeter
+	suffix string
+}
+
+func (w *wrappedGreeter) Greet() string { return w.inner.Greet() + w.suffix }
+
+func TestDecorate_AppliesInRegistrationOrder(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterInterface[testGreeter](c, "greeter", func(c Vessel) (testGreeter, error) {
+		return plainGreeter{}, nil
+	}))
+
+	require.NoError(t, Decorate(c, "greeter", func(c Vessel, g testGreeter) (testGreeter, error) {
+		return &wrappedGreeter{inner: g, suffix: " world"}, nil
+	}))
+	require.NoError(t, Decorate(c, "greeter", func(c Vessel, g testGreeter) (testGreeter, error) {
+		return &wrappedGreeter{inner: g, suffix: "!"}, nil
+	}))
+
+	g := Must[testGreeter](c, "greeter")
+	assert.Equal(t, "hello world!", g.Greet())
+
+	info := c.Inspect("greeter")
+	assert.Equal(t, "2", info.Metadata["__decorators"])
+}
+
+func TestDecorate_RespectsCaching(t *testing.T) {
+	c := New()
+	calls := 0
+
+	require.NoError(t, RegisterSingleton(c, "single", func(c Vessel) (*testService, error) {
+		return &testService{value: "s"}, nil
+	}))
+	require.NoError(t, RegisterScoped(c, "scoped", func(c Vessel) (*testService, error) {
+		return &testService{value: "sc"}, nil
+	}))
+
+	decorator := func(c Vessel, s *testService) (*testService, error) {
+		calls++
+
+		return &testService{value: s.value + "+"}, nil
+	}
+
+	require.NoError(t, Decorate(c, "single", decorator))
+	require.NoError(t, Decorate(c, "scoped", decorator))
+
+	first := Must[*testService](c, "single")
+	second := Must[*testService](c, "single")
+	assert.Same(t, first, second)
+	assert.Equal(t, "s+", first.value)
+	assert.Equal(t, 1, calls)
+
+	scope := c.BeginScope()
+	defer func() { _ = scope.End() }()
+
+	a := MustScope[*testService](scope, "scoped")
+	b := MustScope[*testService](scope, "scoped")
+	assert.Same(t, a, b)
+	assert.Equal(t, "sc+", a.value)
+	assert.Equal(t, 2, calls)
+}
+
+func TestDecorate_Errors(t *testing.T) {
+	c := New()
+
+	err := Decorate(c, "missing", func(c Vessel, s *testService) (*testService, error) {
+		return s, nil
+	})
+	assert.ErrorIs(t, err, ErrServiceNotFound("missing"))
+
+	require.NoError(t, RegisterValue(c, "svc", &testService{value: "v"}))
+	require.NoError(t, Decorate(c, "svc", func(c Vessel, s *testService) (*testService, error) {
+		return nil, errors.New("decorator failed")
+	}))
+
+	_, err = c.Resolve("svc")
+	assert.ErrorContains(t, err, "decorator failed")
+
+	// Type mismatch between decorator and instance
+	require.NoError(t, RegisterValue(c, "str", "text"))
+	require.NoError(t, Decorate(c, "str", func(c Vessel, s *testService) (*testService, error) {
+		return s, nil
+	}))
+
+	_, err = c.Resolve("str")
+	assert.ErrorIs(t, err, ErrTypeMismatchSentinel)
+}
+
+func TestDecorate_AfterInstantiationFails(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterValue(c, "svc", &testService{value: "v"}))
+
+	_, err := c.Resolve("svc")
+	require.NoError(t, err)
+
+	err = Decorate(c, "svc", func(c Vessel, s *testService) (*testService, error) {
+		return s, nil
+	})
+	assert.ErrorContains(t, err, "already instantiated")
+}
+
+func TestDecorate_RevertedByRestore(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterValue(c, "svc", &testService{value: "v"}))
+
+	snap, err := Snapshot(c)
+	require.NoError(t, err)
+
+	require.NoError(t, Decorate(c, "svc", func(c Vessel, s *testService) (*testService, error) {
+		return &testService{value: "decorated"}, nil
+	}))
+	require.NoError(t, Restore(c, snap))
+
+	assert.Equal(t, "v", Must[*testService](c, "svc").value)
+}
+
+func TestDecorate_ModuleServices(t *testing.T) {
+	c := New()
+	require.NoError(t, Install(c, Module("billing",
+		Service("repo", newServiceFactory("repo")),
+		Service("service", newServiceFactory("service")),
+		Export("service"),
+	)))
+
+	decorate := func(c Vessel, s *testService) (*testService, error) {
+		return &testService{value: s.value + " decorated"}, nil
+	}
+
+	// A decorated private service stays private
+	require.NoError(t, Decorate(c, "billing.repo", decorate))
+	assert.False(t, c.Has("billing.repo"))
+
+	// The export and its qualified name still share one decorated singleton
+	require.NoError(t, Decorate(c, "service", decorate))
+
+	svc := Must[*testService](c, "service")
+	assert.Equal(t, "service decorated", svc.value)
+	assert.Same(t, svc, Must[*testService](c, "billing.service"))
+}
+
+func TestDecorateType(t *testing.T) {
+	c := New()
+	require.NoError(t, ProvideConstructor(c, newTestDatabase, WithAliases("main")))
+	require.NoError(t, ProvideConstructor(c, func() *testLogger { return &testLogger{level: "debug"} }))
+
+	err := DecorateType(c, func(db *testDatabase, log *testLogger) *testDatabase {
+		return &testDatabase{connStr: db.connStr + "?log=" + log.level}
+	})
+	require.NoError(t, err)
+
+	db, err := InjectType[*testDatabase](c)
+	require.NoError(t, err)
+	assert.Contains(t, db.connStr, "?log=debug")
+
+	// Aliases share the decorated registration
+	aliased, err := InjectNamed[*testDatabase](c, "main")
+	require.NoError(t, err)
+	assert.Same(t, db, aliased)
+}
+
+func TestDecorateType_InvalidDecorators(t *testing.T) {
+	c := New()
+	require.NoError(t, ProvideConstructor(c, newTestDatabase))
+
+	// Does not accept the decorated type
+	assert.Error(t, DecorateType(c, func(log *testLogger) *testDatabase { return nil }))
+
+	// Not a function
+	assert.Error(t, DecorateType(c, "nope"))
+
+	// Nothing registered for the type
+	assert.Error(t, DecorateType(c, func(l *testLogger) *testLogger { return l }))
+
+	// Decorator error is surfaced
+	require.NoError(t, DecorateType(c, func(db *testDatabase) (*testDatabase, error) {
+		return nil, errors.New("wrap failed")
+	}))
+
+	_, err := InjectType[*testDatabase](c)
+	assert.ErrorContains(t, err, "wrap failed")
+}
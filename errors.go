@@ -31,6 +31,12 @@ const (
 
 	// CodeTypeMismatch indicates a type mismatch during service resolution
 	CodeTypeMismatch = "TYPE_MISMATCH"
+
+	// CodeCaptiveDependency indicates a longer-lived service depends on a shorter-lived one
+	CodeCaptiveDependency = "CAPTIVE_DEPENDENCY"
+
+	// CodeNoScope indicates a context carries no scope
+	CodeNoScope = "NO_SCOPE"
 )
 
 // =============================================================================
@@ -52,6 +58,12 @@ var ErrScopeEnded = errs.NewError(CodeScopeEnded, "scope has ended", nil)
 // ErrTypeMismatchSentinel is a sentinel error for type mismatch during resolution.
 var ErrTypeMismatchSentinel = errs.NewError(CodeTypeMismatch, "type mismatch", nil)
 
+// ErrCaptiveDependencySentinel is a sentinel error for captive dependencies (for error checking).
+var ErrCaptiveDependencySentinel = errs.NewError(CodeCaptiveDependency, "captive dependency", nil)
+
+// ErrNoScope is returned when resolving from a context that carries no scope.
+var ErrNoScope = errs.NewError(CodeNoScope, "no scope in context", nil)
+
 // =============================================================================
 // ERROR CONSTRUCTORS
 // =============================================================================
@@ -102,3 +114,14 @@ func ErrTypeMismatch(serviceName string, actual any) *errs.Error {
 	).WithContext("service", serviceName).
 		WithContext("actual_type", fmt.Sprintf("%T", actual)).(*errs.Error)
 }
+
+// ErrCaptiveDependency creates an error for a service capturing a shorter-lived dependency
+func ErrCaptiveDependency(dep CaptiveDependency) *errs.Error {
+	return errs.NewError(
+		CodeCaptiveDependency,
+		fmt.Sprintf("%s service '%s' depends on %s service '%s'",
+			dep.Lifecycle, dep.Service, dep.DependencyLifecycle, dep.Dependency),
+		nil,
+	).WithContext("service", dep.Service).
+		WithContext("dependency", dep.Dependency).(*errs.Error)
+}
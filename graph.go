@@ -26,24 +26,46 @@ func NewDependencyGraph() *DependencyGraph {
 
 // AddNode adds a node with its dependencies (string-based, backward compatible).
 // Nodes are processed in the order they are added (FIFO) when no dependencies exist.
+// Adding an existing node replaces its dependencies and keeps its position.
 func (g *DependencyGraph) AddNode(name string, dependencies []string) {
-	g.nodes[name] = &node{
+	g.setNode(&node{
 		name:         name,
 		dependencies: dependencies,
 		deps:         di.DepsFromNames(dependencies), // Convert to Dep specs
-	}
-	g.order = append(g.order, name)
+	})
 }
 
 // AddNodeWithDeps adds a node with full Dep specs.
 // This is the new API that supports lazy/optional dependencies.
 func (g *DependencyGraph) AddNodeWithDeps(name string, deps []di.Dep) {
-	g.nodes[name] = &node{
+	g.setNode(&node{
 		name:         name,
 		dependencies: di.DepNames(deps), // Keep string names for backward compat
 		deps:         deps,
+	})
+}
+
+// setNode stores n, appending it to the registration order only if new.
+func (g *DependencyGraph) setNode(n *node) {
+	if _, exists := g.nodes[n.name]; !exists {
+		g.order = append(g.order, n.name)
+	}
+
+	g.nodes[n.name] = n
+}
+
+// clone returns a copy of the graph. Nodes are never mutated in place,
+// so they are shared between the copies.
+func (g *DependencyGraph) clone() *DependencyGraph {
+	nodes := make(map[string]*node, len(g.nodes))
+	for name, n := range g.nodes {
+		nodes[name] = n
+	}
+
+	return &DependencyGraph{
+		nodes: nodes,
+		order: append([]string(nil), g.order...),
 	}
-	g.order = append(g.order, name)
 }
 
 // GetDependencies returns the dependency names for a node.
@@ -126,15 +148,23 @@ func (g *DependencyGraph) TopologicalSortEagerOnly() ([]string, error) {
 
 // visit performs DFS traversal.
 func (g *DependencyGraph) visit(name string, visited, visiting map[string]bool, result *[]string) error {
+	return g.walk(name, visited, visiting, result, nil, false)
+}
+
+// visitEagerOnly performs DFS traversal considering only eager dependencies.
+func (g *DependencyGraph) visitEagerOnly(name string, visited, visiting map[string]bool, result *[]string) error {
+	return g.walk(name, visited, visiting, result, nil, true)
+}
+
+// walk performs the DFS traversal behind visit and visitEagerOnly. path
+// holds the nodes being visited, so a cycle is reported in full.
+func (g *DependencyGraph) walk(name string, visited, visiting map[string]bool, result *[]string, path []string, eagerOnly bool) error {
 	if visited[name] {
 		return nil
 	}
 
 	if visiting[name] {
-		// Build the cycle chain for better error message
-		cycle := []string{name}
-
-		return ErrCircularDependency(cycle)
+		return ErrCircularDependency(cyclePath(path, name))
 	}
 
 	node := g.nodes[name]
@@ -144,10 +174,15 @@ func (g *DependencyGraph) visit(name string, visited, visiting map[string]bool,
 	}
 
 	visiting[name] = true
+	path = append(path, name)
 
 	// Visit dependencies first
-	for _, dep := range node.dependencies {
-		if err := g.visit(dep, visited, visiting, result); err != nil {
+	for _, dep := range node.deps {
+		if eagerOnly && dep.Mode.IsLazy() {
+			continue
+		}
+
+		if err := g.walk(dep.Name, visited, visiting, result, path, eagerOnly); err != nil {
 			return err
 		}
 	}
@@ -159,37 +194,123 @@ func (g *DependencyGraph) visit(name string, visited, visiting map[string]bool,
 	return nil
 }
 
-// visitEagerOnly performs DFS traversal considering only eager dependencies.
-func (g *DependencyGraph) visitEagerOnly(name string, visited, visiting map[string]bool, result *[]string) error {
-	if visited[name] {
-		return nil
+// cyclePath returns the part of path from name onwards, closed with name
+// again, e.g. [a b c a].
+func cyclePath(path []string, name string) []string {
+	start := len(path) - 1
+	for start > 0 && path[start] != name {
+		start--
 	}
 
-	if visiting[name] {
-		cycle := []string{name}
+	return append(append([]string(nil), path[start:]...), name)
+}
+
+// knownDependencies returns, for each of the given nodes, its dependencies
+// that are themselves registered in the graph, without duplicates.
+// Unknown dependencies are left out because nothing will ever start them.
+func (g *DependencyGraph) knownDependencies(names []string) map[string][]string {
+	result := make(map[string][]string, len(names))
+
+	for _, name := range names {
+		node := g.nodes[name]
+		if node == nil {
+			continue
+		}
+
+		seen := make(map[string]bool, len(node.dependencies))
+		for _, dep := range node.dependencies {
+			if seen[dep] || g.nodes[dep] == nil {
+				continue
+			}
 
-		return ErrCircularDependency(cycle)
+			seen[dep] = true
+			result[name] = append(result[name], dep)
+		}
 	}
 
-	node := g.nodes[name]
-	if node == nil {
-		return nil
+	return result
+}
+
+// cycles returns one dependency path per back edge found by a depth-first
+// walk in registration order, so every strongly connected set of nodes is
+// reported at least once. Each path starts and ends with the same node. Dependencies
+// on unknown nodes are ignored.
+func (g *DependencyGraph) cycles() [][]string {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+
+	state := make(map[string]int, len(g.nodes))
+	stack := make([]string, 0, len(g.nodes))
+
+	var (
+		result [][]string
+		walk   func(name string)
+	)
+
+	walk = func(name string) {
+		state[name] = onStack
+		stack = append(stack, name)
+
+		for _, dep := range g.nodes[name].dependencies {
+			if g.nodes[dep] == nil {
+				continue
+			}
+
+			switch state[dep] {
+			case unvisited:
+				walk(dep)
+			case onStack:
+				result = append(result, cyclePath(stack, dep))
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = done
 	}
 
-	visiting[name] = true
+	for _, name := range g.order {
+		if state[name] == unvisited {
+			walk(name)
+		}
+	}
 
-	// Visit only eager (non-lazy) dependencies
-	for _, dep := range node.deps {
-		if !dep.Mode.IsLazy() {
-			if err := g.visitEagerOnly(dep.Name, visited, visiting, result); err != nil {
-				return err
+	return result
+}
+
+// dependents returns name and every node depending on it directly or
+// transitively, in the sequence they appear in order.
+func (g *DependencyGraph) dependents(name string, order []string) []string {
+	reverse := make(map[string][]string, len(g.nodes))
+	for _, n := range g.nodes {
+		for _, dep := range n.dependencies {
+			reverse[dep] = append(reverse[dep], n.name)
+		}
+	}
+
+	affected := map[string]bool{name: true}
+	queue := []string{name}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dependent := range reverse[current] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
 			}
 		}
 	}
 
-	visiting[name] = false
-	visited[name] = true
-	*result = append(*result, name)
+	result := make([]string, 0, len(affected))
+	for _, n := range order {
+		if affected[n] {
+			result = append(result, n)
+		}
+	}
 
-	return nil
+	return result
 }
@@ -0,0 +1,548 @@
+package vessel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xraph/go-utils/di"
+)
+
+// GraphSchemaVersion is the version of the JSON form of Graph. It only
+// changes when a field is renamed, removed or changes meaning.
+const GraphSchemaVersion = 1
+
+// GraphNodeKind says what a graph node stands for.
+type GraphNodeKind string
+
+const (
+	// GraphNodeService is a name-based service.
+	GraphNodeService GraphNodeKind = "service"
+
+	// GraphNodeType is a type-based service from ProvideConstructor,
+	// identified by its type key.
+	GraphNodeType GraphNodeKind = "type"
+
+	// GraphNodeExternal is a dependency registered in a parent container.
+	GraphNodeExternal GraphNodeKind = "external"
+
+	// GraphNodeMissing is a dependency that is not registered.
+	GraphNodeMissing GraphNodeKind = "missing"
+)
+
+// GraphNode is a service in an exported graph.
+type GraphNode struct {
+	ID        string            `json:"id"` // Service name, or type key for GraphNodeType
+	Kind      GraphNodeKind     `json:"kind"`
+	Type      string            `json:"type,omitempty"` // Instance type, or result type for GraphNodeType
+	Lifecycle string            `json:"lifecycle,omitempty"`
+	Module    string            `json:"module,omitempty"`
+	Aliases   []string          `json:"aliases,omitempty"`
+	Groups    []string          `json:"groups,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"` // Without internal "__" keys
+	Created   bool              `json:"created"`
+	Started   bool              `json:"started"`          // Type-based services are never started
+	Health    HealthStatus      `json:"health,omitempty"` // Created health checkers only
+}
+
+// GraphEdge is a dependency of From on To.
+type GraphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Mode  string `json:"mode"`            // eager, lazy, optional or lazy_optional, as di.DepMode
+	Group string `json:"group,omitempty"` // Value group a constructor collects To through
+}
+
+// Graph is a snapshot of a service graph, for rendering as Graphviz DOT,
+// Mermaid or JSON. Nodes come in registration order, followed by
+// type-based services sorted by ID and then dependencies that are not
+// part of the graph. Edges follow their From node's order.
+type Graph struct {
+	Version int         `json:"version"`
+	Nodes   []GraphNode `json:"nodes"`
+	Edges   []GraphEdge `json:"edges"`
+}
+
+// Export returns the graph's nodes and declared dependencies, without any
+// of the state a container adds in ExportGraph.
+func (g *DependencyGraph) Export() *Graph {
+	b := newGraphBuilder()
+
+	for _, name := range g.order {
+		node := g.nodes[name]
+		if node == nil {
+			continue
+		}
+
+		b.node(GraphNode{ID: name, Kind: GraphNodeService})
+
+		for _, dep := range node.deps {
+			b.edge(GraphEdge{From: name, To: dep.Name, Mode: dep.Mode.String()})
+		}
+	}
+
+	return b.build()
+}
+
+// ExportGraph returns the container's service graph. See
+// (*containerImpl).ExportGraph.
+//
+// Example:
+//
+//	graph, _ := vessel.ExportGraph(ctx, c)
+//	os.WriteFile("services.dot", []byte(graph.DOT()), 0o644)
+func ExportGraph(ctx context.Context, c Vessel) (*Graph, error) {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return nil, fmt.Errorf("ExportGraph requires *containerImpl, got %T", c)
+	}
+
+	return impl.ExportGraph(ctx), nil
+}
+
+// ExportGraph returns the container's name-based and type-based services
+// with their dependencies, annotated with lifecycle, groups, metadata and
+// state. Module aliases are folded into the service they name.
+//
+// Health comes from the health monitor's cache when one is running;
+// otherwise every created health checker is checked as by HealthReport.
+// No factory runs.
+func (c *containerImpl) ExportGraph(ctx context.Context) *Graph {
+	var report *HealthReport
+	if c.monitor != nil {
+		report = c.monitor.report()
+	} else {
+		report = c.HealthReport(ctx)
+	}
+
+	health := make(map[string]HealthStatus, len(report.Services))
+	for _, result := range report.Services {
+		health[result.Name] = result.Status
+	}
+
+	b := newGraphBuilder()
+
+	c.exportServices(b, health)
+	c.exportTypes(b)
+
+	return b.build()
+}
+
+// exportServices adds the name-based services and their dependencies.
+func (c *containerImpl) exportServices(b *graphBuilder, health map[string]HealthStatus) {
+	c.mu.RLock()
+
+	aliases := make(map[*serviceRegistration][]string)
+
+	for _, name := range c.graph.order {
+		if reg, exists := c.services[name]; exists && reg.name != name {
+			aliases[reg] = append(aliases[reg], name)
+		}
+	}
+
+	var unregistered []string
+
+	for _, name := range c.graph.order {
+		reg, exists := c.services[name]
+		if !exists || reg.name != name {
+			continue
+		}
+
+		b.node(serviceNode(reg, aliases[reg], health))
+
+		for _, dep := range reg.deps {
+			to := dep.Name
+			if target, ok := c.services[to]; ok {
+				to = target.name
+			} else {
+				unregistered = append(unregistered, to)
+			}
+
+			b.edge(GraphEdge{From: name, To: to, Mode: dep.Mode.String()})
+		}
+	}
+
+	c.mu.RUnlock()
+
+	// Checked without the lock, as the parent may be any container
+	for _, name := range unregistered {
+		if c.parent != nil && c.parent.Has(name) {
+			b.outside(name, GraphNodeExternal)
+		}
+	}
+}
+
+// serviceNode describes a name-based registration.
+func serviceNode(reg *serviceRegistration, aliases []string, health map[string]HealthStatus) GraphNode {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	node := GraphNode{
+		ID:        reg.name,
+		Kind:      GraphNodeService,
+		Lifecycle: reg.lifecycle(),
+		Module:    reg.metadata["__module"],
+		Aliases:   aliases,
+		Groups:    append([]string(nil), reg.groups...),
+		Created:   reg.instance != nil,
+		Started:   reg.started,
+		Health:    health[reg.name],
+	}
+
+	if node.Created {
+		node.Type = fmt.Sprintf("%T", reg.instance)
+	}
+
+	for key, value := range reg.metadata {
+		if strings.HasPrefix(key, "__") {
+			continue
+		}
+
+		if node.Metadata == nil {
+			node.Metadata = make(map[string]string)
+		}
+
+		node.Metadata[key] = value
+	}
+
+	return node
+}
+
+// exportTypes adds the type-based services and the dependencies of their
+// constructors.
+func (c *containerImpl) exportTypes(b *graphBuilder) {
+	if c.typeRegistry == nil {
+		return
+	}
+
+	c.typeRegistry.mu.RLock()
+
+	// Aliases share their registration; As types have their own
+	var regs []*typeRegistration
+
+	aliases := make(map[*typeRegistration][]string)
+
+	for key, reg := range c.typeRegistry.services {
+		if key == reg.key {
+			regs = append(regs, reg)
+		} else {
+			aliases[reg] = append(aliases[reg], key.String())
+		}
+	}
+
+	c.typeRegistry.mu.RUnlock()
+
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].key.String() < regs[j].key.String()
+	})
+
+	for _, reg := range regs {
+		id := reg.key.String()
+		sort.Strings(aliases[reg])
+
+		reg.mu.RLock()
+		created := reg.instance != nil
+		reg.mu.RUnlock()
+
+		b.node(GraphNode{
+			ID:        id,
+			Kind:      GraphNodeType,
+			Type:      reg.key.typ.String(),
+			Lifecycle: reg.lifecycle,
+			Aliases:   aliases[reg],
+			Groups:    append([]string(nil), reg.groups...),
+			Created:   created,
+		})
+
+		if reg.constructor == nil {
+			continue
+		}
+
+		for _, param := range reg.constructor.params {
+			if !param.isIn {
+				c.exportParam(b, id, param)
+
+				continue
+			}
+
+			for _, field := range param.inFields {
+				c.exportParam(b, id, field)
+			}
+		}
+	}
+}
+
+// exportParam adds the edges for a constructor parameter or In field,
+// mirroring resolveParam and resolveGroup.
+func (c *containerImpl) exportParam(b *graphBuilder, from string, param paramInfo) {
+	mode := di.DepEager
+	if param.optional {
+		mode = di.DepOptional
+	}
+
+	if param.group {
+		for _, member := range c.findGroup(param.groupKey) {
+			to := member.key.String()
+
+			b.outside(to, GraphNodeExternal)
+			b.edge(GraphEdge{From: from, To: to, Mode: mode.String(), Group: param.groupKey})
+		}
+
+		return
+	}
+
+	if param.typ == lifecycleType && param.name == "" {
+		return
+	}
+
+	to := paramKey(param)
+	if reg, ok := c.findType(typeKey{typ: param.typ, name: param.name}); ok {
+		to = reg.key.String()
+		b.outside(to, GraphNodeExternal)
+	}
+
+	b.edge(GraphEdge{From: from, To: to, Mode: mode.String()})
+}
+
+// graphBuilder assembles a Graph, adding a node for every edge target
+// that is not part of the graph.
+type graphBuilder struct {
+	graph    *Graph
+	nodes    map[string]bool
+	edges    map[GraphEdge]bool
+	outsides map[string]GraphNodeKind // Kind of a target if it has no node
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{
+		graph:    &Graph{Version: GraphSchemaVersion, Nodes: []GraphNode{}, Edges: []GraphEdge{}},
+		nodes:    make(map[string]bool),
+		edges:    make(map[GraphEdge]bool),
+		outsides: make(map[string]GraphNodeKind),
+	}
+}
+
+// node adds a node of the graph.
+func (b *graphBuilder) node(node GraphNode) {
+	b.nodes[node.ID] = true
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+// edge adds an edge once.
+func (b *graphBuilder) edge(edge GraphEdge) {
+	if b.edges[edge] {
+		return
+	}
+
+	b.edges[edge] = true
+	b.graph.Edges = append(b.graph.Edges, edge)
+}
+
+// outside records the kind of id in case it turns out to have no node.
+// Targets without a recorded kind are GraphNodeMissing.
+func (b *graphBuilder) outside(id string, kind GraphNodeKind) {
+	b.outsides[id] = kind
+}
+
+// build adds the nodes for edge targets outside the graph, in the order
+// they are first referenced.
+func (b *graphBuilder) build() *Graph {
+	for _, edge := range b.graph.Edges {
+		if b.nodes[edge.To] {
+			continue
+		}
+
+		kind, ok := b.outsides[edge.To]
+		if !ok {
+			kind = GraphNodeMissing
+		}
+
+		b.node(GraphNode{ID: edge.To, Kind: kind})
+	}
+
+	return b.graph
+}
+
+// DOT renders the graph in the Graphviz DOT language. Type-based services
+// are rounded, started services green and unhealthy ones red; external
+// and missing dependencies are dashed. Lazy dependencies are dashed
+// edges and optional ones end in a circle.
+//
+// Example:
+//
+//	dot -Tsvg services.dot -o services.svg
+func (g *Graph) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph vessel {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "\t%s [%s];\n", dotQuote(node.ID), node.dotAttributes())
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", dotQuote(edge.From), dotQuote(edge.To), edge.dotAttributes())
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// dotAttributes returns the node's DOT attribute list.
+func (n GraphNode) dotAttributes() string {
+	label := dotEscape(n.ID)
+	if n.Lifecycle != "" {
+		label += `\n` + n.Lifecycle
+	}
+
+	attrs := []string{`label="` + label + `"`}
+
+	var styles []string
+
+	switch n.Kind {
+	case GraphNodeType:
+		styles = append(styles, "rounded")
+	case GraphNodeExternal:
+		styles = append(styles, "dashed")
+	case GraphNodeMissing:
+		styles = append(styles, "dashed")
+		attrs = append(attrs, "color=red")
+	}
+
+	switch {
+	case n.Health == HealthUnhealthy:
+		styles = append(styles, "filled")
+		attrs = append(attrs, "fillcolor=lightpink")
+	case n.Started:
+		styles = append(styles, "filled")
+		attrs = append(attrs, "fillcolor=palegreen")
+	}
+
+	if len(styles) > 0 {
+		attrs = append(attrs, `style="`+strings.Join(styles, ",")+`"`)
+	}
+
+	return strings.Join(attrs, ", ")
+}
+
+// dotAttributes returns the edge's DOT attribute list, if any.
+func (e GraphEdge) dotAttributes() string {
+	var attrs []string
+
+	if label := e.label(); label != "" {
+		attrs = append(attrs, `label="`+dotEscape(label)+`"`)
+	}
+
+	if strings.HasPrefix(e.Mode, "lazy") {
+		attrs = append(attrs, "style=dashed")
+	}
+
+	if strings.HasSuffix(e.Mode, "optional") {
+		attrs = append(attrs, "arrowhead=odot")
+	}
+
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+// label describes what sets the edge apart from a plain eager dependency.
+func (e GraphEdge) label() string {
+	var parts []string
+
+	if e.Mode != di.DepEager.String() {
+		parts = append(parts, e.Mode)
+	}
+
+	if e.Group != "" {
+		parts = append(parts, "group="+e.Group)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// dotQuote returns s as a DOT ID.
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// dotEscape escapes s for use in a quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, with the same
+// conventions as DOT: type-based services are rounded, lazy dependencies
+// dotted, and started, unhealthy, external and missing services styled
+// through classes.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.ID] = id
+
+		label := mermaidEscape(node.ID)
+		if node.Lifecycle != "" {
+			label += "<br/>" + node.Lifecycle
+		}
+
+		shape := `["%s"]`
+		if node.Kind == GraphNodeType {
+			shape = `("%s")`
+		}
+
+		fmt.Fprintf(&b, "\t%s"+shape+"%s\n", id, label, node.mermaidClass())
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if strings.HasPrefix(edge.Mode, "lazy") {
+			arrow = "-.->"
+		}
+
+		if label := edge.label(); label != "" {
+			arrow += `|"` + mermaidEscape(label) + `"|`
+		}
+
+		fmt.Fprintf(&b, "\t%s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+
+	b.WriteString("\tclassDef started fill:#d4edda,stroke:#28a745\n")
+	b.WriteString("\tclassDef unhealthy fill:#f8d7da,stroke:#dc3545\n")
+	b.WriteString("\tclassDef external stroke-dasharray:5 5\n")
+	b.WriteString("\tclassDef missing stroke:#dc3545,stroke-dasharray:5 5\n")
+
+	return b.String()
+}
+
+// mermaidClass returns the class suffix styling the node, if any.
+func (n GraphNode) mermaidClass() string {
+	switch {
+	case n.Kind == GraphNodeMissing:
+		return ":::missing"
+	case n.Kind == GraphNodeExternal:
+		return ":::external"
+	case n.Health == HealthUnhealthy:
+		return ":::unhealthy"
+	case n.Started:
+		return ":::started"
+	default:
+		return ""
+	}
+}
+
+// mermaidEscape escapes s for use in a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,263 @@
+package vessel
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xraph/go-utils/di"
+)
+
+// exportedGraph returns c's graph, failing the test on error.
+func exportedGraph(t *testing.T, c Vessel) *Graph {
+	t.Helper()
+
+	graph, err := ExportGraph(context.Background(), c)
+	require.NoError(t, err)
+
+	return graph
+}
+
+// nodeByID returns the node with the given ID.
+func nodeByID(t *testing.T, graph *Graph, id string) GraphNode {
+	t.Helper()
+
+	for _, node := range graph.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+
+	t.Fatalf("no node %s", id)
+
+	return GraphNode{}
+}
+
+func TestDependencyGraph_Export(t *testing.T) {
+	g := NewDependencyGraph()
+	g.AddNodeWithDeps("api", []di.Dep{di.Eager("db"), di.Lazy("cache"), di.LazyOptional("tracer")})
+	g.AddNode("db", nil)
+	g.AddNode("cache", nil)
+
+	graph := g.Export()
+
+	assert.Equal(t, GraphSchemaVersion, graph.Version)
+	assert.Equal(t, []GraphNode{
+		{ID: "api", Kind: GraphNodeService},
+		{ID: "db", Kind: GraphNodeService},
+		{ID: "cache", Kind: GraphNodeService},
+		{ID: "tracer", Kind: GraphNodeMissing},
+	}, graph.Nodes)
+	assert.Equal(t, []GraphEdge{
+		{From: "api", To: "db", Mode: "eager"},
+		{From: "api", To: "cache", Mode: "lazy"},
+		{From: "api", To: "tracer", Mode: "lazy_optional"},
+	}, graph.Edges)
+}
+
+func TestExportGraph_AnnotatesServices(t *testing.T) {
+	c := New()
+	checker := &toggleChecker{}
+	checker.down.Store(true)
+
+	require.NoError(t, RegisterValue(c, "db", checker))
+	require.NoError(t, c.Register("api", newServiceFactory("api"),
+		di.WithDeps(di.Eager("db"), di.Optional("cache")),
+		WithGroup("http"),
+		WithDIMetadata("team", "core"),
+	))
+	require.NoError(t, c.Register("job", newServiceFactory("job"), Transient()))
+	require.NoError(t, Install(c, Module("billing",
+		Service("invoices", newServiceFactory("invoices")),
+		Export("invoices"),
+	)))
+
+	for _, name := range []string{"api", "db"} {
+		_, err := c.Resolve(name)
+		require.NoError(t, err)
+	}
+
+	graph := exportedGraph(t, c)
+
+	db := nodeByID(t, graph, "db")
+	assert.True(t, db.Created)
+	assert.True(t, db.Started)
+	assert.Equal(t, HealthUnhealthy, db.Health)
+
+	api := nodeByID(t, graph, "api")
+	assert.Equal(t, "singleton", api.Lifecycle)
+	assert.Equal(t, "*vessel.testService", api.Type)
+	assert.Equal(t, []string{"http"}, api.Groups)
+	assert.Equal(t, map[string]string{"team": "core"}, api.Metadata, "internal metadata is left out")
+	assert.Empty(t, api.Health, "only health checkers have a health")
+
+	job := nodeByID(t, graph, "job")
+	assert.Equal(t, "transient", job.Lifecycle)
+	assert.False(t, job.Created)
+	assert.Empty(t, job.Type)
+
+	// Module aliases are folded into their service
+	invoices := nodeByID(t, graph, "billing.invoices")
+	assert.Equal(t, "billing", invoices.Module)
+	assert.Equal(t, []string{"invoices"}, invoices.Aliases)
+
+	for _, node := range graph.Nodes {
+		assert.NotEqual(t, "invoices", node.ID)
+	}
+
+	assert.Equal(t, GraphNodeMissing, nodeByID(t, graph, "cache").Kind)
+	assert.Equal(t, []GraphEdge{
+		{From: "api", To: "db", Mode: "eager"},
+		{From: "api", To: "cache", Mode: "optional"},
+	}, graph.Edges)
+}
+
+func TestExportGraph_TypeBasedServices(t *testing.T) {
+	c := New()
+
+	type params struct {
+		In
+
+		Logger *testLogger
+		Caches []*testCache `group:"caches"`
+		Maybe  *testCache   `optional:"true"`
+	}
+
+	require.NoError(t, ProvideConstructor(c, newTestLogger, WithAliases("audit")))
+	require.NoError(t, ProvideConstructor(c, newTestCache, WithName("redis"), AsGroup("caches")))
+	require.NoError(t, ProvideConstructor(c, newTestUserService))
+	require.NoError(t, ProvideConstructor(c, func(p params) *testService { return &testService{} }))
+
+	_, err := InjectType[*testLogger](c)
+	require.NoError(t, err)
+
+	graph := exportedGraph(t, c)
+
+	logger := nodeByID(t, graph, "*vessel.testLogger")
+	assert.Equal(t, GraphNodeType, logger.Kind)
+	assert.Equal(t, "*vessel.testLogger", logger.Type)
+	assert.Equal(t, "singleton", logger.Lifecycle)
+	assert.Equal(t, []string{"*vessel.testLogger[name=audit]"}, logger.Aliases)
+	assert.True(t, logger.Created)
+	assert.False(t, logger.Started)
+
+	assert.Equal(t, []string{"caches"}, nodeByID(t, graph, "*vessel.testCache[name=redis]").Groups)
+	assert.Equal(t, GraphNodeMissing, nodeByID(t, graph, "*vessel.testDatabase").Kind)
+
+	assert.ElementsMatch(t, []GraphEdge{
+		{From: "*vessel.testService", To: "*vessel.testLogger", Mode: "eager"},
+		{From: "*vessel.testService", To: "*vessel.testCache[name=redis]", Mode: "eager", Group: "caches"},
+		{From: "*vessel.testService", To: "*vessel.testCache", Mode: "optional"},
+		{From: "*vessel.testUserService", To: "*vessel.testDatabase", Mode: "eager"},
+		{From: "*vessel.testUserService", To: "*vessel.testLogger", Mode: "eager"},
+	}, graph.Edges)
+}
+
+func TestExportGraph_ParentServicesAreExternal(t *testing.T) {
+	parent := New()
+	require.NoError(t, parent.Register("db", newServiceFactory("db")))
+
+	child := NewChild(parent)
+	require.NoError(t, child.Register("repo", newServiceFactory("repo"), WithDependencies("db", "cache")))
+
+	graph := exportedGraph(t, child)
+
+	assert.Equal(t, GraphNodeExternal, nodeByID(t, graph, "db").Kind)
+	assert.Equal(t, GraphNodeMissing, nodeByID(t, graph, "cache").Kind)
+}
+
+func TestExportGraph_RequiresContainer(t *testing.T) {
+	_, err := ExportGraph(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+// renderedGraph covers every node kind, state and edge mode.
+var renderedGraph = &Graph{
+	Version: GraphSchemaVersion,
+	Nodes: []GraphNode{
+		{ID: "api", Kind: GraphNodeService, Lifecycle: "singleton", Started: true},
+		{ID: "db", Kind: GraphNodeService, Lifecycle: "singleton", Health: HealthUnhealthy},
+		{ID: "*app.Repo", Kind: GraphNodeType, Lifecycle: "transient"},
+		{ID: "config", Kind: GraphNodeExternal},
+		{ID: `say "hi"`, Kind: GraphNodeMissing},
+	},
+	Edges: []GraphEdge{
+		{From: "api", To: "db", Mode: "eager"},
+		{From: "api", To: "*app.Repo", Mode: "lazy"},
+		{From: "*app.Repo", To: "config", Mode: "optional", Group: "settings"},
+		{From: "api", To: `say "hi"`, Mode: "lazy_optional"},
+	},
+}
+
+func TestGraph_DOT(t *testing.T) {
+	assert.Equal(t, `digraph vessel {
+	rankdir=LR;
+	node [shape=box];
+	"api" [label="api\nsingleton", fillcolor=palegreen, style="filled"];
+	"db" [label="db\nsingleton", fillcolor=lightpink, style="filled"];
+	"*app.Repo" [label="*app.Repo\ntransient", style="rounded"];
+	"config" [label="config", style="dashed"];
+	"say \"hi\"" [label="say \"hi\"", color=red, style="dashed"];
+	"api" -> "db";
+	"api" -> "*app.Repo" [label="lazy", style=dashed];
+	"*app.Repo" -> "config" [label="optional group=settings", arrowhead=odot];
+	"api" -> "say \"hi\"" [label="lazy_optional", style=dashed, arrowhead=odot];
+}
+`, renderedGraph.DOT())
+}
+
+func TestGraph_Mermaid(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+	n0["api<br/>singleton"]:::started
+	n1["db<br/>singleton"]:::unhealthy
+	n2("*app.Repo<br/>transient")
+	n3["config"]:::external
+	n4["say #quot;hi#quot;"]:::missing
+	n0 --> n1
+	n0 -.->|"lazy"| n2
+	n2 -->|"optional group=settings"| n3
+	n0 -.->|"lazy_optional"| n4
+	classDef started fill:#d4edda,stroke:#28a745
+	classDef unhealthy fill:#f8d7da,stroke:#dc3545
+	classDef external stroke-dasharray:5 5
+	classDef missing stroke:#dc3545,stroke-dasharray:5 5
+`, renderedGraph.Mermaid())
+}
+
+func TestGraph_JSON(t *testing.T) {
+	graph := &Graph{
+		Version: GraphSchemaVersion,
+		Nodes: []GraphNode{
+			{
+				ID: "api", Kind: GraphNodeService, Type: "*app.API", Lifecycle: "singleton",
+				Module: "web", Aliases: []string{"http"}, Groups: []string{"handlers"},
+				Metadata: map[string]string{"team": "core"}, Created: true, Started: true, Health: HealthHealthy,
+			},
+			{ID: "db", Kind: GraphNodeMissing},
+		},
+		Edges: []GraphEdge{{From: "api", To: "db", Mode: "lazy", Group: "stores"}},
+	}
+
+	data, err := json.Marshal(graph)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"version": 1,
+		"nodes": [
+			{
+				"id": "api", "kind": "service", "type": "*app.API", "lifecycle": "singleton",
+				"module": "web", "aliases": ["http"], "groups": ["handlers"],
+				"metadata": {"team": "core"}, "created": true, "started": true, "health": "healthy"
+			},
+			{"id": "db", "kind": "missing", "created": false, "started": false}
+		],
+		"edges": [{"from": "api", "to": "db", "mode": "lazy", "group": "stores"}]
+	}`, string(data))
+
+	// An empty graph still has arrays
+	data, err = json.Marshal(NewDependencyGraph().Export())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": 1, "nodes": [], "edges": []}`, string(data))
+}
@@ -52,6 +52,21 @@ func TestDependencyGraph_TopologicalSort_CircularDependency(t *testing.T) {
 	assert.ErrorIs(t, err, ErrCircularDependencySentinel)
 }
 
+func TestDependencyGraph_TopologicalSort_CyclePath(t *testing.T) {
+	g := NewDependencyGraph()
+	g.AddNode("root", []string{"a"})
+	g.AddNode("a", []string{"b"})
+	g.AddNode("b", []string{"c"})
+	g.AddNode("c", []string{"a"})
+
+	_, err := g.TopologicalSort()
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[a b c a]")
+
+	_, err = g.TopologicalSortEagerOnly()
+	assert.Contains(t, err.Error(), "[a b c a]")
+}
+
 func TestDependencyGraph_TopologicalSort_SelfReference(t *testing.T) {
 	g := NewDependencyGraph()
 	g.AddNode("a", []string{"a"})
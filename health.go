@@ -0,0 +1,275 @@
+package vessel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xraph/go-utils/di"
+)
+
+// DefaultHealthTimeout is the per-check deadline used when none is set
+// with WithHealthTimeout.
+const DefaultHealthTimeout = 5 * time.Second
+
+// criticalMetadataKey marks services whose failure does not make the
+// container unhealthy.
+const criticalMetadataKey = "__critical"
+
+// HealthStatus is the outcome of a health check.
+type HealthStatus string
+
+const (
+	// HealthHealthy means every check passed.
+	HealthHealthy HealthStatus = "healthy"
+
+	// HealthDegraded means only non-critical services failed.
+	HealthDegraded HealthStatus = "degraded"
+
+	// HealthUnhealthy means a critical service failed.
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// ServiceHealth is the health check result of one service.
+type ServiceHealth struct {
+	Name     string
+	Status   HealthStatus // HealthHealthy or HealthUnhealthy
+	Critical bool
+	Latency  time.Duration
+	Err      error
+}
+
+// MarshalJSON renders the error as a message and the latency as a duration string.
+func (h ServiceHealth) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Name     string       `json:"name"`
+		Status   HealthStatus `json:"status"`
+		Critical bool         `json:"critical"`
+		Latency  string       `json:"latency"`
+		Error    string       `json:"error,omitempty"`
+	}{
+		Name:     h.Name,
+		Status:   h.Status,
+		Critical: h.Critical,
+		Latency:  h.Latency.String(),
+	}
+
+	if h.Err != nil {
+		out.Error = h.Err.Error()
+	}
+
+	return json.Marshal(out)
+}
+
+// HealthReport is the result of checking every instantiated singleton
+// that implements di.HealthChecker.
+type HealthReport struct {
+	Status    HealthStatus
+	Services  []ServiceHealth // Sorted by name
+	CheckedAt time.Time
+	Duration  time.Duration
+}
+
+// MarshalJSON renders the duration as a duration string.
+func (r *HealthReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Status    HealthStatus    `json:"status"`
+		Services  []ServiceHealth `json:"services"`
+		CheckedAt time.Time       `json:"checked_at"`
+		Duration  string          `json:"duration"`
+	}{
+		Status:    r.Status,
+		Services:  r.Services,
+		CheckedAt: r.CheckedAt,
+		Duration:  r.Duration.String(),
+	})
+}
+
+// NonCritical marks a service as non-critical for health reporting.
+// When it fails its health check the container is reported as degraded
+// rather than unhealthy.
+func NonCritical() RegisterOption {
+	return WithDIMetadata(criticalMetadataKey, "false")
+}
+
+// WithHealthTimeout sets the deadline for each service's health check.
+// A check that runs longer is reported as failed. Defaults to
+// DefaultHealthTimeout.
+func WithHealthTimeout(timeout time.Duration) ContainerOption {
+	return func(o *containerOptions) {
+		o.healthTimeout = timeout
+	}
+}
+
+// CheckHealth runs every health check of the container and returns a report.
+//
+// Example:
+//
+//	report, err := vessel.CheckHealth(ctx, c)
+//	if err == nil && report.Status == vessel.HealthUnhealthy {
+//	    // page someone
+//	}
+func CheckHealth(ctx context.Context, c Vessel) (*HealthReport, error) {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return nil, fmt.Errorf("CheckHealth requires *containerImpl, got %T", c)
+	}
+
+	return impl.HealthReport(ctx), nil
+}
+
+// healthTarget is a service to check.
+type healthTarget struct {
+	name     string
+	checker  di.HealthChecker
+	critical bool
+}
+
+// HealthReport checks every instantiated singleton that implements
+// di.HealthChecker in parallel, each under its own deadline. The container
+// lock is only held while collecting the services, not during the checks.
+func (c *containerImpl) HealthReport(ctx context.Context) *HealthReport {
+	started := time.Now()
+	targets := c.healthTargets()
+
+	results := make([]ServiceHealth, len(targets))
+
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = c.checkHealth(ctx, target)
+		}()
+	}
+
+	wg.Wait()
+
+	return &HealthReport{
+		Status:    summarizeHealth(results),
+		Services:  results,
+		CheckedAt: started,
+		Duration:  time.Since(started),
+	}
+}
+
+// summarizeHealth derives the overall status from per-service results.
+func summarizeHealth(results []ServiceHealth) HealthStatus {
+	status := HealthHealthy
+
+	for _, result := range results {
+		if result.Status == HealthHealthy {
+			continue
+		}
+
+		if result.Critical {
+			return HealthUnhealthy
+		}
+
+		status = HealthDegraded
+	}
+
+	return status
+}
+
+// healthTargets collects the services to check, sorted by name.
+func (c *containerImpl) healthTargets() []healthTarget {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var targets []healthTarget
+
+	for name, reg := range c.services {
+		// Module aliases share their target's registration
+		if reg.name != name || !reg.singleton {
+			continue
+		}
+
+		reg.mu.RLock()
+		instance := reg.instance
+		reg.mu.RUnlock()
+
+		checker, ok := instance.(di.HealthChecker)
+		if !ok {
+			continue
+		}
+
+		targets = append(targets, healthTarget{
+			name:     name,
+			checker:  checker,
+			critical: reg.metadata[criticalMetadataKey] != "false",
+		})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].name < targets[j].name
+	})
+
+	return targets
+}
+
+// checkHealth runs a single check under the container's health timeout.
+func (c *containerImpl) checkHealth(ctx context.Context, target healthTarget) ServiceHealth {
+	timeout := c.options.healthTimeout
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	begin := time.Now()
+
+	err := callWithDeadline(checkCtx, target.checker.Health)
+	if err == errAbandoned {
+		err = fmt.Errorf("health check abandoned: %w", checkCtx.Err())
+	}
+
+	result := ServiceHealth{
+		Name:     target.name,
+		Status:   HealthHealthy,
+		Critical: target.critical,
+		Latency:  time.Since(begin),
+		Err:      err,
+	}
+
+	if err != nil {
+		result.Status = HealthUnhealthy
+	}
+
+	return result
+}
+
+// HealthHandler serves the container's health report as JSON, for
+// readiness and liveness probes. It responds 200 when the container is
+// healthy or degraded and 503 when it is unhealthy.
+//
+// Example:
+//
+//	mux.Handle("/healthz", vessel.HealthHandler(c))
+func HealthHandler(c Vessel) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, err := CheckHealth(r.Context(), c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		status := http.StatusOK
+		if report.Status == HealthUnhealthy {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,266 @@
+package vessel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHealthInterval is how often the health monitor checks services
+// when HealthMonitorOptions.Interval is not set.
+const DefaultHealthInterval = 30 * time.Second
+
+// HealthMonitorOptions configures the background health monitor.
+type HealthMonitorOptions struct {
+	// Interval between checks. Defaults to DefaultHealthInterval.
+	Interval time.Duration
+
+	// FailureThreshold is the number of consecutive failed checks before a
+	// healthy service is reported unhealthy. Defaults to 1.
+	FailureThreshold int
+
+	// SuccessThreshold is the number of consecutive passed checks before an
+	// unhealthy service is reported healthy again. Defaults to 1.
+	// Raising both thresholds keeps a flapping service from firing a
+	// change on every check.
+	SuccessThreshold int
+
+	// OnChange is called from the monitor goroutine whenever a service's
+	// reported status changes.
+	OnChange func(change HealthChange)
+}
+
+// HealthChange describes a service moving between healthy and unhealthy.
+type HealthChange struct {
+	Name string
+	From HealthStatus
+	To   HealthStatus
+	Err  error // The failure that caused the change, nil when recovering
+	At   time.Time
+}
+
+// WithHealthMonitor checks every di.HealthChecker service in the background
+// while the container is started. Inspect and Query then read the cached
+// status instead of running health checks themselves.
+//
+// Services are assumed healthy when the monitor first sees them.
+//
+// Example:
+//
+//	c := vessel.New(vessel.WithHealthMonitor(vessel.HealthMonitorOptions{
+//	    Interval:         10 * time.Second,
+//	    FailureThreshold: 3,
+//	    OnChange: func(ch vessel.HealthChange) {
+//	        log.Printf("%s is now %s: %v", ch.Name, ch.To, ch.Err)
+//	    },
+//	}))
+func WithHealthMonitor(opts HealthMonitorOptions) ContainerOption {
+	return func(o *containerOptions) {
+		o.healthMonitor = &opts
+	}
+}
+
+// CachedHealthReport returns the health monitor's latest results without
+// running any check. The status of each service reflects the monitor's
+// thresholds, while latency and error come from its latest check.
+func CachedHealthReport(c Vessel) (*HealthReport, error) {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return nil, fmt.Errorf("CachedHealthReport requires *containerImpl, got %T", c)
+	}
+
+	if impl.monitor == nil {
+		return nil, fmt.Errorf("container has no health monitor")
+	}
+
+	return impl.monitor.report(), nil
+}
+
+// monitoredService is the monitor's view of one service.
+type monitoredService struct {
+	last      ServiceHealth
+	status    HealthStatus // After applying thresholds
+	failures  int          // Consecutive failed checks
+	successes int          // Consecutive passed checks
+}
+
+// healthMonitor periodically checks a container's services.
+type healthMonitor struct {
+	options   HealthMonitorOptions
+	services  map[string]*monitoredService
+	checkedAt time.Time
+	cancel    context.CancelFunc
+	done      chan struct{}
+	mu        sync.RWMutex
+}
+
+// newHealthMonitor applies defaults to opts.
+func newHealthMonitor(opts HealthMonitorOptions) *healthMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultHealthInterval
+	}
+
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 1
+	}
+
+	if opts.SuccessThreshold <= 0 {
+		opts.SuccessThreshold = 1
+	}
+
+	return &healthMonitor{
+		options:  opts,
+		services: make(map[string]*monitoredService),
+	}
+}
+
+// start launches the check loop; the first check runs immediately.
+func (m *healthMonitor) start(c *containerImpl) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	m.cancel, m.done = cancel, done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(m.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			m.update(c.HealthReport(ctx))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop ends the check loop and waits for it to exit.
+func (m *healthMonitor) stop() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// update records a report and fires OnChange for status changes.
+func (m *healthMonitor) update(report *HealthReport) {
+	var changes []HealthChange
+
+	m.mu.Lock()
+
+	seen := make(map[string]bool, len(report.Services))
+
+	for _, result := range report.Services {
+		seen[result.Name] = true
+
+		svc, ok := m.services[result.Name]
+		if !ok {
+			svc = &monitoredService{status: HealthHealthy}
+			m.services[result.Name] = svc
+		}
+
+		svc.last = result
+
+		next := svc.status
+
+		if result.Err == nil {
+			svc.successes++
+			svc.failures = 0
+
+			if svc.successes >= m.options.SuccessThreshold {
+				next = HealthHealthy
+			}
+		} else {
+			svc.failures++
+			svc.successes = 0
+
+			if svc.failures >= m.options.FailureThreshold {
+				next = HealthUnhealthy
+			}
+		}
+
+		if next != svc.status {
+			changes = append(changes, HealthChange{
+				Name: result.Name,
+				From: svc.status,
+				To:   next,
+				Err:  result.Err,
+				At:   report.CheckedAt,
+			})
+			svc.status = next
+		}
+	}
+
+	// Forget services that are no longer checked
+	for name := range m.services {
+		if !seen[name] {
+			delete(m.services, name)
+		}
+	}
+
+	m.checkedAt = report.CheckedAt
+
+	m.mu.Unlock()
+
+	if m.options.OnChange != nil {
+		for _, change := range changes {
+			m.options.OnChange(change)
+		}
+	}
+}
+
+// status returns the cached status of a service.
+func (m *healthMonitor) status(name string) (HealthStatus, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	svc, ok := m.services[name]
+	if !ok {
+		return "", false
+	}
+
+	return svc.status, true
+}
+
+// report builds a health report from the cached results.
+func (m *healthMonitor) report() *HealthReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	services := make([]ServiceHealth, 0, len(m.services))
+	for _, svc := range m.services {
+		result := svc.last
+		result.Status = svc.status
+		services = append(services, result)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	return &HealthReport{
+		Status:    summarizeHealth(services),
+		Services:  services,
+		CheckedAt: m.checkedAt,
+	}
+}
@@ -0,0 +1,159 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toggleChecker fails while down is set and counts its checks.
+type toggleChecker struct {
+	down  atomic.Bool
+	calls atomic.Int32
+}
+
+func (t *toggleChecker) Health(ctx context.Context) error {
+	t.calls.Add(1)
+
+	if t.down.Load() {
+		return errors.New("down")
+	}
+
+	return nil
+}
+
+// changeRecorder collects health changes from the monitor goroutine.
+type changeRecorder struct {
+	changes []HealthChange
+	mu      sync.Mutex
+}
+
+func (r *changeRecorder) record(change HealthChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.changes = append(r.changes, change)
+}
+
+func (r *changeRecorder) get() []HealthChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]HealthChange(nil), r.changes...)
+}
+
+func TestHealthMonitor_NotifiesChanges(t *testing.T) {
+	recorder := &changeRecorder{}
+	checker := &toggleChecker{}
+
+	c := New(WithHealthMonitor(HealthMonitorOptions{
+		Interval: 5 * time.Millisecond,
+		OnChange: recorder.record,
+	}))
+	require.NoError(t, RegisterValue(c, "db", checker))
+	require.NoError(t, c.Start(context.Background()))
+
+	defer func() { _ = c.Stop(context.Background()) }()
+
+	checker.down.Store(true)
+	require.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, time.Millisecond)
+
+	checker.down.Store(false)
+	require.Eventually(t, func() bool { return len(recorder.get()) == 2 }, time.Second, time.Millisecond)
+
+	changes := recorder.get()
+	assert.Equal(t, "db", changes[0].Name)
+	assert.Equal(t, HealthHealthy, changes[0].From)
+	assert.Equal(t, HealthUnhealthy, changes[0].To)
+	assert.EqualError(t, changes[0].Err, "down")
+	assert.Equal(t, HealthHealthy, changes[1].To)
+	assert.NoError(t, changes[1].Err)
+}
+
+func TestHealthMonitor_Thresholds(t *testing.T) {
+	m := newHealthMonitor(HealthMonitorOptions{FailureThreshold: 3, SuccessThreshold: 2})
+	recorder := &changeRecorder{}
+	m.options.OnChange = recorder.record
+
+	check := func(err error) {
+		status := HealthHealthy
+		if err != nil {
+			status = HealthUnhealthy
+		}
+
+		m.update(&HealthReport{Services: []ServiceHealth{
+			{Name: "api", Status: status, Critical: true, Err: err},
+		}})
+	}
+
+	failure := errors.New("timeout")
+
+	// Flapping below the failure threshold never fires
+	check(failure)
+	check(failure)
+	check(nil)
+	check(failure)
+	assert.Empty(t, recorder.get())
+
+	status, ok := m.status("api")
+	require.True(t, ok)
+	assert.Equal(t, HealthHealthy, status)
+
+	check(failure)
+	check(failure)
+	require.Len(t, recorder.get(), 1)
+	assert.Equal(t, HealthUnhealthy, recorder.get()[0].To)
+
+	// Recovery needs two passes in a row
+	check(nil)
+	assert.Len(t, recorder.get(), 1)
+	check(nil)
+	assert.Len(t, recorder.get(), 2)
+
+	// Services that disappear are forgotten
+	m.update(&HealthReport{})
+	_, ok = m.status("api")
+	assert.False(t, ok)
+}
+
+func TestHealthMonitor_CachesForInspect(t *testing.T) {
+	checker := &toggleChecker{}
+
+	c := New(WithHealthMonitor(HealthMonitorOptions{Interval: time.Hour}))
+	require.NoError(t, RegisterValue(c, "db", checker))
+	require.NoError(t, c.Start(context.Background()))
+
+	// Wait for the first check to be cached
+	require.Eventually(t, func() bool {
+		report, err := CachedHealthReport(c)
+
+		return err == nil && len(report.Services) == 1
+	}, time.Second, time.Millisecond)
+
+	for range 5 {
+		assert.True(t, c.Inspect("db").Healthy)
+	}
+
+	_ = QueryNames(c, ServiceQuery{})
+	assert.Equal(t, int32(1), checker.calls.Load(), "Inspect and Query must use the cache")
+
+	report, err := CachedHealthReport(c)
+	require.NoError(t, err)
+	assert.Equal(t, HealthHealthy, report.Status)
+	require.Len(t, report.Services, 1)
+	assert.Equal(t, "db", report.Services[0].Name)
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Nil(t, c.(*containerImpl).monitor.cancel, "monitor stops with the container")
+}
+
+func TestCachedHealthReport_NoMonitor(t *testing.T) {
+	_, err := CachedHealthReport(New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package vessel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// funcChecker is a health checker backed by a function.
+type funcChecker func(ctx context.Context) error
+
+func (f funcChecker) Health(ctx context.Context) error { return f(ctx) }
+
+func registerChecker(t *testing.T, c Vessel, name string, check funcChecker, opts ...RegisterOption) {
+	t.Helper()
+
+	require.NoError(t, c.Register(name, func(c Vessel) (any, error) {
+		return check, nil
+	}, opts...))
+
+	_, err := c.Resolve(name)
+	require.NoError(t, err)
+}
+
+func TestHealthReport_Statuses(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("down") }
+
+	c := New()
+	registerChecker(t, c, "db", ok)
+	registerChecker(t, c, "cache", ok)
+
+	report := c.(*containerImpl).HealthReport(context.Background())
+	assert.Equal(t, HealthHealthy, report.Status)
+	require.Len(t, report.Services, 2)
+	assert.Equal(t, "cache", report.Services[0].Name, "sorted by name")
+	assert.True(t, report.Services[0].Critical)
+
+	// Non-critical failure degrades
+	registerChecker(t, c, "metrics", fail, NonCritical())
+
+	report, err := CheckHealth(context.Background(), c)
+	require.NoError(t, err)
+	assert.Equal(t, HealthDegraded, report.Status)
+	assert.NoError(t, c.Health(context.Background()), "Health ignores non-critical failures")
+
+	// Critical failure makes the container unhealthy
+	registerChecker(t, c, "queue", fail)
+
+	report, err = CheckHealth(context.Background(), c)
+	require.NoError(t, err)
+	assert.Equal(t, HealthUnhealthy, report.Status)
+	assert.EqualError(t, report.Services[3].Err, "down")
+	assert.Equal(t, HealthUnhealthy, report.Services[3].Status)
+
+	err = c.Health(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "queue")
+}
+
+func TestHealthReport_ParallelWithTimeout(t *testing.T) {
+	c := New(WithHealthTimeout(50 * time.Millisecond))
+
+	slow := func(ctx context.Context) error {
+		time.Sleep(200 * time.Millisecond)
+
+		return nil
+	}
+
+	registerChecker(t, c, "a", slow)
+	registerChecker(t, c, "b", slow)
+	registerChecker(t, c, "c", func(ctx context.Context) error { return nil })
+
+	begin := time.Now()
+	report, err := CheckHealth(context.Background(), c)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(begin), 150*time.Millisecond, "checks run in parallel and time out")
+	assert.Equal(t, HealthUnhealthy, report.Status)
+	assert.ErrorIs(t, report.Services[0].Err, context.DeadlineExceeded)
+	assert.NoError(t, report.Services[2].Err)
+}
+
+func TestHealthReport_DoesNotHoldLock(t *testing.T) {
+	c := New()
+
+	// A check that registers a service would deadlock under the read lock
+	registerChecker(t, c, "registrar", func(ctx context.Context) error {
+		return RegisterValue(c, "late", "value")
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- c.Health(context.Background()) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("health check deadlocked")
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	c := New()
+	healthy := true
+
+	registerChecker(t, c, "db", func(ctx context.Context) error {
+		if healthy {
+			return nil
+		}
+
+		return errors.New("connection refused")
+	})
+
+	rec := httptest.NewRecorder()
+	HealthHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	healthy = false
+
+	rec = httptest.NewRecorder()
+	HealthHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var body struct {
+		Status   string `json:"status"`
+		Services []struct {
+			Name  string `json:"name"`
+			Error string `json:"error"`
+		} `json:"services"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "unhealthy", body.Status)
+	require.Len(t, body.Services, 1)
+	assert.Equal(t, "connection refused", body.Services[0].Error)
+}
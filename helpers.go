@@ -26,6 +26,33 @@ func Resolve[T any](c Vessel, name string) (T, error) {
 	return typed, nil
 }
 
+// ResolveCtx resolves with type safety, passing ctx through the resolution.
+// Containers that cannot take a context fall back to a plain Resolve.
+func ResolveCtx[T any](ctx context.Context, c Vessel, name string) (T, error) {
+	var zero T
+
+	instance, err := resolveContext(ctx, c, name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("service %s: type mismatch, expected %T but got %T", name, zero, instance)
+	}
+
+	return typed, nil
+}
+
+// resolveContext resolves through ResolveContext when c supports it.
+func resolveContext(ctx context.Context, c Vessel, name string) (any, error) {
+	if resolver, ok := c.(ContextResolver); ok {
+		return resolver.ResolveContext(ctx, name)
+	}
+
+	return c.Resolve(name)
+}
+
 // Must resolves or panics - use only during startup.
 func Must[T any](c Vessel, name string) T {
 	instance, err := Resolve[T](c, name)
@@ -66,6 +93,24 @@ func MustResolveReady[T any](ctx context.Context, c Vessel, name string) T {
 	return instance
 }
 
+// RegisterContext registers a factory that receives the context of the
+// resolution creating the instance (ResolveContext, ResolveReady or Start).
+// Plain Resolve calls pass context.Background().
+//
+// Usage:
+//
+//	vessel.RegisterContext(c, "database", func(ctx context.Context, c vessel.Vessel) (any, error) {
+//	    return sql.Open(...).PingContext(ctx)
+//	})
+func RegisterContext(c Vessel, name string, factory ContextFactory, opts ...RegisterOption) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("RegisterContext requires *containerImpl, got %T", c)
+	}
+
+	return impl.RegisterContext(name, factory, opts...)
+}
+
 // RegisterSingleton is a convenience wrapper for singleton services.
 func RegisterSingleton[T any](c Vessel, name string, factory func(Vessel) (T, error)) error {
 	return c.Register(name, func(c Vessel) (any, error) {
@@ -184,6 +229,34 @@ func ResolveScope[T any](s Scope, name string) (T, error) {
 	return typed, nil
 }
 
+// ResolveScopeCtx resolves from a scope with type safety, passing ctx
+// through the resolution.
+func ResolveScopeCtx[T any](ctx context.Context, s Scope, name string) (T, error) {
+	var zero T
+
+	var (
+		instance any
+		err      error
+	)
+
+	if resolver, ok := s.(ContextResolver); ok {
+		instance, err = resolver.ResolveContext(ctx, name)
+	} else {
+		instance, err = s.Resolve(name)
+	}
+
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("service %s: type mismatch, expected %T but got %T", name, zero, instance)
+	}
+
+	return typed, nil
+}
+
 // MustScope resolves from scope or panics.
 func MustScope[T any](s Scope, name string) T {
 	instance, err := ResolveScope[T](s, name)
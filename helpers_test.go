@@ -708,3 +708,44 @@ func TestRegisterSingletonWith_SingleReturnFactory(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "single-return", svc.db.connStr)
 }
+
+func TestResolveCtx_TypeSafe(t *testing.T) {
+	c := New()
+
+	err := RegisterContext(c, "test", func(ctx context.Context, c Vessel) (any, error) {
+		return &testService{value: ctx.Value(testCtxKey("v")).(string)}, nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), testCtxKey("v"), "from-ctx")
+
+	svc, err := ResolveCtx[*testService](ctx, c, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "from-ctx", svc.value)
+
+	_, err = ResolveCtx[*testImpl](ctx, c, "test")
+	assert.Error(t, err)
+}
+
+func TestResolveScopeCtx_ScopedContextFactory(t *testing.T) {
+	c := New()
+
+	err := RegisterContext(c, "request", func(ctx context.Context, c Vessel) (any, error) {
+		return &testService{value: ctx.Value(testCtxKey("req")).(string)}, nil
+	}, Scoped())
+	require.NoError(t, err)
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	ctx := context.WithValue(context.Background(), testCtxKey("req"), "req-1")
+
+	svc, err := ResolveScopeCtx[*testService](ctx, s, "request")
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", svc.value)
+
+	// Cached within the scope
+	again, err := ResolveScope[*testService](s, "request")
+	require.NoError(t, err)
+	assert.Same(t, svc, again)
+}
@@ -11,6 +11,8 @@ import (
 type InjectOption struct {
 	Dep      di.Dep
 	TypeInfo reflect.Type
+
+	fromScope bool // Dep.Name is a scope context key, not a service
 }
 
 // Inject creates an eager injection option for a dependency.
@@ -123,21 +125,53 @@ func ProviderInject[T any](name string) InjectOption {
 	}
 }
 
+// FromScope creates an injection option for a value stored in the current
+// scope's context with SetScoped, rather than a registered service. The
+// service must be resolved from a scope, and the value must be set and
+// assignable to T. Scope values are not dependencies, so they don't appear
+// in the dependency graph.
+//
+// Usage:
+//
+//	vessel.RegisterScopedWith[*Profile](c, "profile",
+//	    vessel.FromScope[*User]("currentUser"),
+//	    func(user *User) (*Profile, error) { ... },
+//	)
+func FromScope[T any](key string) InjectOption {
+	var zero T
+
+	return InjectOption{
+		Dep: di.Dep{
+			Name: key,
+			Type: reflect.TypeOf(zero),
+			Mode: di.DepEager,
+		},
+		TypeInfo:  reflect.TypeOf((*T)(nil)).Elem(),
+		fromScope: true,
+	}
+}
+
 // ExtractDeps extracts dependency specifications from inject options.
+// FromScope options are skipped since they don't name services.
 func ExtractDeps(opts []InjectOption) []di.Dep {
-	deps := make([]di.Dep, len(opts))
-	for i, opt := range opts {
-		deps[i] = opt.Dep
+	deps := make([]di.Dep, 0, len(opts))
+	for _, opt := range opts {
+		if !opt.fromScope {
+			deps = append(deps, opt.Dep)
+		}
 	}
 
 	return deps
 }
 
 // ExtractDepNames extracts just the names from inject options.
+// FromScope options are skipped since they don't name services.
 func ExtractDepNames(opts []InjectOption) []string {
-	names := make([]string, len(opts))
-	for i, opt := range opts {
-		names[i] = opt.Dep.Name
+	names := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		if !opt.fromScope {
+			names = append(names, opt.Dep.Name)
+		}
 	}
 
 	return names
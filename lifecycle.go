@@ -0,0 +1,278 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Hook is a pair of callbacks run by the container's Start and Stop.
+// Either callback may be nil.
+type Hook struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle collects start and stop hooks from factories and constructors.
+// It lets types that don't implement di.Service, such as *http.Server or
+// *sql.DB, take part in Start and Stop without an adapter.
+//
+// Hooks appended while a service is being created belong to that service,
+// or, for a transient, to the service that resolved it. Start runs a
+// service's hooks in the order they were appended right after the service
+// starts; Stop runs OnStop in reverse, before stopping the service. Hooks
+// appended once the container is running start on the next Start.
+//
+// Hooks appended by the constructor of a type-based singleton belong to
+// that type. Start runs them before the first service that needs them, or
+// right away for a singleton created while the container is running, and
+// Stop runs them after every name-based service has stopped. Hooks
+// appended outside any factory are run the same way.
+//
+// Example:
+//
+//	vessel.ProvideConstructor(c, func(lc vessel.Lifecycle, h http.Handler) *http.Server {
+//	    srv := &http.Server{Addr: ":8080", Handler: h}
+//	    lc.Append(vessel.Hook{
+//	        OnStart: func(ctx context.Context) error {
+//	            go srv.ListenAndServe()
+//	            return nil
+//	        },
+//	        OnStop: srv.Shutdown,
+//	    })
+//	    return srv
+//	})
+type Lifecycle interface {
+	Append(hook Hook)
+}
+
+// lifecycleType is used to inject the Lifecycle into constructors.
+var lifecycleType = reflect.TypeOf((*Lifecycle)(nil)).Elem()
+
+// LifecycleOf returns the Lifecycle for the Vessel a name-based factory
+// received, so the hooks it appends belong to the service being created.
+// Constructors can take a Lifecycle parameter instead.
+func LifecycleOf(c Vessel) (Lifecycle, error) {
+	root := containerOf(c)
+
+	if view, ok := root.(*moduleView); ok {
+		root = view.root
+	}
+
+	impl, ok := root.(*containerImpl)
+	if !ok {
+		return nil, fmt.Errorf("LifecycleOf requires *containerImpl, got %T", root)
+	}
+
+	return impl.lifecycleFor(c), nil
+}
+
+// lifecycleFor returns the Lifecycle for v, owned by the service v is
+// building if it is a factory's Vessel from this container.
+func (c *containerImpl) lifecycleFor(v Vessel) Lifecycle {
+	for {
+		switch current := v.(type) {
+		case *moduleView:
+			v = current.via
+		case *typeResolution:
+			return ownedLifecycle{lifecycle: c.lifecycle, owner: current.owner}
+		case *resolution:
+			if current.container != c || current.current.hookOwner() == "" {
+				return c.lifecycle
+			}
+
+			return ownedLifecycle{lifecycle: c.lifecycle, owner: current.current.hookOwner()}
+		default:
+			return c.lifecycle
+		}
+	}
+}
+
+// lifecycleHook is an appended hook and who it belongs to: a service name,
+// the typeKey of a type-based singleton, or "" for the container itself.
+type lifecycleHook struct {
+	hook    Hook
+	owner   any
+	claimed bool
+	started bool
+}
+
+// detached reports whether owner is not a name-based service, so its hooks
+// are started and stopped around the services rather than with one.
+func detached(owner any) bool {
+	_, isType := owner.(typeKey)
+
+	return isType || owner == ""
+}
+
+// ownedBy matches the hooks owned by the service name.
+func ownedBy(name string) func(owner any) bool {
+	return func(owner any) bool { return owner == name }
+}
+
+// lifecycle implements Lifecycle.
+type lifecycle struct {
+	hooks []*lifecycleHook
+	mu    sync.Mutex
+}
+
+// newLifecycle creates an empty lifecycle.
+func newLifecycle() *lifecycle {
+	return &lifecycle{}
+}
+
+// Append adds a hook without an owner.
+func (l *lifecycle) Append(hook Hook) {
+	l.append(hook, "")
+}
+
+// append adds a hook owned by owner.
+func (l *lifecycle) append(hook Hook, owner any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, &lifecycleHook{hook: hook, owner: owner})
+}
+
+// ownedLifecycle is the Lifecycle a service's factory or a type-based
+// singleton's constructor sees. Hooks appended through it belong to that
+// service or type.
+type ownedLifecycle struct {
+	*lifecycle
+
+	owner any
+}
+
+// Append adds a hook owned by the service or type.
+func (l ownedLifecycle) Append(hook Hook) {
+	l.append(hook, l.owner)
+}
+
+// claim returns the hooks owned by owner that haven't run yet. A service,
+// or "" for the container itself, also claims the hooks of type-based
+// singletons, which were created before it, and those appended without an
+// owner, which it takes over. The hooks keep their type as owner, so Stop
+// leaves them running until the services are down.
+func (l *lifecycle) claim(owner any) []*lifecycleHook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, forType := owner.(typeKey)
+
+	var claimed []*lifecycleHook
+
+	for _, h := range l.hooks {
+		if h.claimed || (h.owner != owner && (forType || !detached(h.owner))) {
+			continue
+		}
+
+		if h.owner == "" {
+			h.owner = owner
+		}
+
+		h.claimed = true
+		claimed = append(claimed, h)
+	}
+
+	return claimed
+}
+
+// start runs the OnStart callbacks of hooks in order, stopping at the
+// first failure. Hooks that started are marked so stop can undo them.
+func (l *lifecycle) start(ctx context.Context, hooks []*lifecycleHook) error {
+	for _, h := range hooks {
+		if h.hook.OnStart != nil {
+			if err := h.hook.OnStart(ctx); err != nil {
+				return fmt.Errorf("lifecycle hook: %w", err)
+			}
+		}
+
+		l.mu.Lock()
+		h.started = true
+		l.mu.Unlock()
+	}
+
+	return nil
+}
+
+// startedBy returns the started hooks whose owner matches, most recent
+// first, and marks them stopped.
+func (l *lifecycle) startedBy(owns func(owner any) bool) []*lifecycleHook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var hooks []*lifecycleHook
+
+	for i := len(l.hooks) - 1; i >= 0; i-- {
+		h := l.hooks[i]
+		if h.started && owns(h.owner) {
+			h.started = false
+			hooks = append(hooks, h)
+		}
+	}
+
+	return hooks
+}
+
+// startHooks runs the hooks claimed by owner (see lifecycle.claim).
+func (c *containerImpl) startHooks(ctx context.Context, owner any) error {
+	return c.lifecycle.start(ctx, c.lifecycle.claim(owner))
+}
+
+// startTypeHooks runs the hooks of a type-based singleton created while
+// the container is running, since no Start will come for them.
+func (c *containerImpl) startTypeHooks(key typeKey) error {
+	c.mu.RLock()
+	running := c.started
+	c.mu.RUnlock()
+
+	if !running {
+		return nil
+	}
+
+	return c.startHooks(context.Background(), key)
+}
+
+// stopHooks runs OnStop for the started hooks whose owner matches, each
+// within its own stop deadline. Every hook is stopped even if others fail.
+func (c *containerImpl) stopHooks(ctx context.Context, owns func(owner any) bool) error {
+	var stopErrs []error
+
+	for _, h := range c.lifecycle.startedBy(owns) {
+		if h.hook.OnStop == nil {
+			continue
+		}
+
+		stopCtx, cancel := c.stopContext(ctx)
+		err := stopWithDeadline(stopCtx, h.hook.OnStop)
+		cancel()
+
+		if err != nil {
+			stopErrs = append(stopErrs, fmt.Errorf("lifecycle hook: %w", err))
+		}
+	}
+
+	return errors.Join(stopErrs...)
+}
+
+// forget removes the hooks owned by owner, once they have been stopped
+// and their service is about to be rebuilt.
+func (l *lifecycle) forget(owner any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = slices.DeleteFunc(l.hooks, func(h *lifecycleHook) bool {
+		return h.owner == owner
+	})
+}
+
+// reset drops every hook, once all of them have been stopped.
+func (l *lifecycle) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = nil
+}
@@ -0,0 +1,300 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookRecorder returns a factory that appends start/stop hooks logging to events.
+func hookRecorder(events *[]string, name string, startErr error) Factory {
+	return func(c Vessel) (any, error) {
+		lc, err := LifecycleOf(c)
+		if err != nil {
+			return nil, err
+		}
+
+		lc.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				*events = append(*events, "start "+name)
+
+				return startErr
+			},
+			OnStop: func(ctx context.Context) error {
+				*events = append(*events, "stop "+name)
+
+				return nil
+			},
+		})
+
+		return &testService{value: name}, nil
+	}
+}
+
+func TestLifecycle_DependencyOrder(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, c.Register("server", hookRecorder(&events, "server", nil), WithDependencies("db")))
+	require.NoError(t, c.Register("db", hookRecorder(&events, "db", nil)))
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start db", "start server"}, events)
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"start db", "start server", "stop server", "stop db"}, events)
+}
+
+func TestLifecycle_InjectedIntoConstructors(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	provideRecordingDatabase(t, c, &events)
+
+	// Constructors run when resolved, here from a name-based service
+	require.NoError(t, c.Register("repo", func(c Vessel) (any, error) {
+		return InjectType[*testDatabase](c)
+	}))
+
+	require.NoError(t, c.Start(context.Background()))
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"open", "close"}, events)
+}
+
+// provideRecordingDatabase provides a *testDatabase whose constructor
+// appends hooks logging to events.
+func provideRecordingDatabase(t *testing.T, c Vessel, events *[]string) {
+	t.Helper()
+
+	require.NoError(t, ProvideConstructor(c, func(lc Lifecycle) *testDatabase {
+		lc.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				*events = append(*events, "open")
+
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				*events = append(*events, "close")
+
+				return nil
+			},
+		})
+
+		return &testDatabase{connStr: "db"}
+	}))
+}
+
+func TestLifecycle_TypeBasedHooksWithoutServices(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	provideRecordingDatabase(t, c, &events)
+
+	// Only type-based services, resolved before Start
+	_, err := InjectType[*testDatabase](c)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"open"}, events)
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"open", "close"}, events)
+}
+
+func TestLifecycle_TypeBasedHooksAfterStart(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	provideRecordingDatabase(t, c, &events)
+	require.NoError(t, c.Start(context.Background()))
+
+	// No later Start would run them, so they start right away
+	_, err := InjectType[*testDatabase](c)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"open"}, events)
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"open", "close"}, events)
+}
+
+func TestLifecycle_ModuleAliasStopsHooksFirst(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, Install(c, Module("m",
+		Service("svc", func(c Vessel) (any, error) {
+			lc, err := LifecycleOf(c)
+			if err != nil {
+				return nil, err
+			}
+
+			lc.Append(Hook{
+				OnStart: func(ctx context.Context) error {
+					events = append(events, "hook start")
+
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					events = append(events, "hook stop")
+
+					return nil
+				},
+			})
+
+			return &mockServiceWithCallback{
+				mockService: mockService{name: "svc"},
+				onStart:     func() { events = append(events, "svc start") },
+				onStop:      func() { events = append(events, "svc stop") },
+			}, nil
+		}),
+		Export("svc"),
+	)))
+
+	require.NoError(t, c.Start(context.Background()))
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"svc start", "hook start", "hook stop", "svc stop"}, events)
+}
+
+func TestLifecycle_RollbackOnHookFailure(t *testing.T) {
+	c := New()
+	events := []string{}
+	hookErr := errors.New("listen failed")
+
+	require.NoError(t, c.Register("db", hookRecorder(&events, "db", nil)))
+	require.NoError(t, c.Register("server", hookRecorder(&events, "server", hookErr), WithDependencies("db")))
+
+	err := c.Start(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, hookErr)
+
+	// The failed hook is not stopped; the one before it is
+	assert.Equal(t, []string{"start db", "start server", "stop db"}, events)
+}
+
+func TestLifecycle_HooksFromEarlyResolve(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, c.Register("db", hookRecorder(&events, "db", nil)))
+
+	// Resolving before Start creates the instance; its hooks wait for Start
+	_, err := c.Resolve("db")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start db"}, events)
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"start db", "stop db"}, events)
+}
+
+func TestLifecycle_StopErrorsAreJoined(t *testing.T) {
+	c := New()
+	stopErr := errors.New("shutdown failed")
+	stopped := false
+
+	require.NoError(t, c.Register("a", func(c Vessel) (any, error) {
+		lc, err := LifecycleOf(c)
+		if err != nil {
+			return nil, err
+		}
+
+		lc.Append(Hook{OnStop: func(ctx context.Context) error { return stopErr }})
+		lc.Append(Hook{OnStop: func(ctx context.Context) error {
+			stopped = true
+
+			return nil
+		}})
+
+		return &mockService{name: "a"}, nil
+	}))
+
+	require.NoError(t, c.Start(context.Background()))
+
+	err := c.Stop(context.Background())
+	assert.ErrorIs(t, err, stopErr)
+	assert.True(t, stopped)
+	assert.False(t, c.IsStarted("a"), "the service itself is still stopped")
+}
+
+func TestLifecycleOf_ModuleView(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, Install(c, Module("m",
+		Service("svc", hookRecorder(&events, "svc", nil)),
+	)))
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start svc"}, events)
+}
+
+func TestLifecycle_ParallelStartKeepsHookOwners(t *testing.T) {
+	c := New(WithParallelStart(0))
+
+	var (
+		mu     sync.Mutex
+		events []string
+		once   sync.Once
+	)
+
+	record := func(event string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			events = append(events, event)
+
+			return nil
+		}
+	}
+
+	aAppended := make(chan struct{})
+	bStarted := make(chan struct{})
+
+	// a appends its hook first, but is still being created when b starts
+	require.NoError(t, c.Register("a", func(c Vessel) (any, error) {
+		lc, err := LifecycleOf(c)
+		if err != nil {
+			return nil, err
+		}
+
+		lc.Append(Hook{OnStart: record("start a"), OnStop: record("stop a")})
+		close(aAppended)
+		<-bStarted
+
+		return &mockService{name: "a"}, nil
+	}))
+	require.NoError(t, c.Register("b", func(c Vessel) (any, error) {
+		<-aAppended
+
+		lc, err := LifecycleOf(c)
+		if err != nil {
+			return nil, err
+		}
+
+		lc.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				once.Do(func() { close(bStarted) })
+
+				return record("start b")(ctx)
+			},
+			OnStop: record("stop b"),
+		})
+
+		return &mockService{name: "b"}, nil
+	}))
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start b", "start a"}, events)
+
+	// Restarting b only touches b's hook
+	require.NoError(t, Restart(context.Background(), c, "b"))
+	assert.Equal(t, []string{"start b", "start a", "stop b", "start b"}, events)
+}
@@ -0,0 +1,446 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ModuleOption is an entry in a module definition: a service, a setup
+// function, a constructor, an export or a nested module.
+type ModuleOption interface {
+	applyModule(m *ModuleDef)
+}
+
+// ModuleDef bundles registrations under a name. Services registered in a
+// module are private to it unless exported. Create one with Module and add
+// it to a container with Install.
+type ModuleDef struct {
+	name         string
+	services     []ServiceRegistration
+	setups       []func(m Vessel) error
+	constructors []moduleConstructor
+	exports      []string
+	modules      []*ModuleDef
+}
+
+// moduleConstructor is a deferred ProvideConstructor call.
+type moduleConstructor struct {
+	constructor any
+	opts        []ConstructorOption
+}
+
+// moduleOptionFunc is a function adapter for ModuleOption.
+type moduleOptionFunc func(*ModuleDef)
+
+func (f moduleOptionFunc) applyModule(m *ModuleDef) { f(m) }
+
+// Module defines a named bundle of registrations.
+//
+// Within a module, services are registered under their short names and
+// resolve each other by those names. From outside, they are private: only
+// names passed to Export are visible to the enclosing module, or to the
+// whole container for a top-level module. Nested modules see the private
+// services of the modules that contain them.
+//
+// Example:
+//
+//	var Billing = vessel.Module("billing",
+//	    vessel.Service("repo", NewRepo),
+//	    vessel.Service("service", NewBillingService, vessel.WithDependencies("repo")),
+//	    vessel.Setup(func(m vessel.Vessel) error {
+//	        return vessel.Provide[*Invoicer](m, "invoicer", vessel.Inject[*Repo]("repo"), NewInvoicer)
+//	    }),
+//	    vessel.Export("service", "invoicer"),
+//	)
+//
+//	err := vessel.Install(c, Billing)
+func Module(name string, opts ...ModuleOption) *ModuleDef {
+	m := &ModuleDef{name: name}
+	for _, opt := range opts {
+		opt.applyModule(m)
+	}
+
+	return m
+}
+
+// Name returns the module name.
+func (m *ModuleDef) Name() string {
+	return m.name
+}
+
+// applyModule nests m inside parent.
+func (m *ModuleDef) applyModule(parent *ModuleDef) {
+	parent.modules = append(parent.modules, m)
+}
+
+// applyModule adds the service to a module.
+func (s ServiceRegistration) applyModule(m *ModuleDef) {
+	m.services = append(m.services, s)
+}
+
+// Setup runs fn against the module while it is installed. Anything fn
+// registers on m (Register, Provide, RegisterSingleton, ...) belongs to
+// the module.
+func Setup(fn func(m Vessel) error) ModuleOption {
+	return moduleOptionFunc(func(m *ModuleDef) {
+		m.setups = append(m.setups, fn)
+	})
+}
+
+// Constructor adds a ProvideConstructor registration to the module.
+// Type-based services are keyed by type rather than name, so they are
+// always visible container-wide.
+func Constructor(constructor any, opts ...ConstructorOption) ModuleOption {
+	return moduleOptionFunc(func(m *ModuleDef) {
+		m.constructors = append(m.constructors, moduleConstructor{constructor: constructor, opts: opts})
+	})
+}
+
+// Export makes services of the module visible to its enclosing module, or
+// to the whole container for a top-level module. A module can re-export
+// names exported by its nested modules.
+func Export(names ...string) ModuleOption {
+	return moduleOptionFunc(func(m *ModuleDef) {
+		m.exports = append(m.exports, names...)
+	})
+}
+
+// Install registers the modules into the container. Installation is not
+// atomic: if a module fails, registrations made before the failure remain.
+func Install(c Vessel, modules ...*ModuleDef) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Install requires *containerImpl, got %T", c)
+	}
+
+	for _, m := range modules {
+		if err := installModule(impl, m, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// installModule registers m's services under qualified names, installs its
+// nested modules and aliases its exports into the enclosing scope.
+func installModule(root *containerImpl, m *ModuleDef, parent *moduleView) error {
+	if m.name == "" {
+		return errors.New("module name cannot be empty")
+	}
+
+	view := &moduleView{
+		root:   root,
+		via:    root,
+		parent: parent,
+		path:   m.name,
+		locals: make(map[string]bool),
+	}
+	if parent != nil {
+		view.path = parent.path + "." + m.name
+	}
+
+	// Collect the module's own registrations
+	view.pending = append(view.pending, m.services...)
+
+	for _, setup := range m.setups {
+		if err := setup(view); err != nil {
+			return fmt.Errorf("module %s: %w", view.path, err)
+		}
+	}
+
+	// Names are known up front so dependencies can be qualified regardless
+	// of registration order
+	for _, svc := range view.pending {
+		view.locals[svc.Name] = true
+	}
+	for _, nested := range m.modules {
+		for _, name := range nested.exports {
+			view.locals[name] = true
+		}
+	}
+
+	pending := view.pending
+	view.pending = nil
+	view.installed = true
+
+	for _, svc := range pending {
+		if err := view.install(svc); err != nil {
+			return err
+		}
+	}
+
+	for _, nested := range m.modules {
+		if err := installModule(root, nested, view); err != nil {
+			return err
+		}
+	}
+
+	for _, mc := range m.constructors {
+		if err := ProvideConstructor(root, mc.constructor, mc.opts...); err != nil {
+			return fmt.Errorf("module %s: %w", view.path, err)
+		}
+	}
+
+	// Alias exports into the enclosing scope
+	for _, name := range m.exports {
+		if !view.locals[name] {
+			return fmt.Errorf("module %s exports unknown service %s", view.path, name)
+		}
+
+		exported := name
+		if parent != nil {
+			exported = parent.path + "." + name
+		}
+
+		if err := root.alias(exported, view.path+"."+name); err != nil {
+			return fmt.Errorf("module %s: %w", view.path, err)
+		}
+
+		// Visible throughout the enclosing module, or everywhere at the top
+		visibleIn := ""
+		if parent != nil {
+			visibleIn = parent.path
+		}
+
+		root.setPrivate(view.path+"."+name, visibleIn)
+	}
+
+	return nil
+}
+
+// moduleView is the Vessel a module's factories and setup functions see.
+// It maps the module's short names onto their qualified registrations and
+// passes everything else to the container.
+type moduleView struct {
+	root      *containerImpl
+	via       Vessel // Resolves qualified names: root, or what a factory received
+	parent    *moduleView
+	path      string
+	locals    map[string]bool
+	pending   []ServiceRegistration
+	installed bool
+}
+
+// qualify maps a name visible in this module to its registered name.
+func (v *moduleView) qualify(name string) string {
+	for current := v; current != nil; current = current.parent {
+		if current.locals[name] {
+			return current.path + "." + name
+		}
+	}
+
+	return name
+}
+
+// install registers svc under its qualified name. Declared dependencies are
+// qualified too, and the factory receives the module view bound to the
+// Vessel the container passes it.
+func (v *moduleView) install(svc ServiceRegistration) error {
+	if svc.Factory == nil {
+		return ErrInvalidFactory
+	}
+
+	merged := mergeOptions(svc.Options)
+
+	deps := merged.GetAllDeps()
+	for i := range deps {
+		deps[i].Name = v.qualify(deps[i].Name)
+	}
+
+	opts := []RegisterOption{
+		{Lifecycle: merged.Lifecycle, Deps: deps, Groups: merged.Groups, Metadata: merged.Metadata},
+		WithDIMetadata("__module", v.path),
+	}
+
+	factory := svc.Factory
+	wrapped := func(c Vessel) (any, error) {
+		return factory(v.bind(c))
+	}
+
+	if err := v.root.Register(v.path+"."+svc.Name, wrapped, opts...); err != nil {
+		return err
+	}
+
+	v.root.setPrivate(v.path+"."+svc.Name, v.path)
+
+	return nil
+}
+
+// bind returns a copy of the view that resolves through c, so a factory's
+// resolutions stay part of the call chain or scope it was given.
+func (v *moduleView) bind(c Vessel) *moduleView {
+	bound := *v
+	bound.via = c
+
+	return &bound
+}
+
+// Register adds a service to the module. Registrations are collected while
+// the module is being installed and registered once all names are known.
+func (v *moduleView) Register(name string, factory Factory, opts ...RegisterOption) error {
+	if v.installed {
+		return fmt.Errorf("module %s is already installed", v.path)
+	}
+
+	if name == "" {
+		return errors.New("service name cannot be empty")
+	}
+
+	v.pending = append(v.pending, Service(name, factory, opts...))
+
+	return nil
+}
+
+// Resolve returns a service by its name as seen from the module.
+func (v *moduleView) Resolve(name string) (any, error) {
+	return v.ResolveContext(context.Background(), name)
+}
+
+// ResolveContext returns a service by its name as seen from the module.
+func (v *moduleView) ResolveContext(ctx context.Context, name string) (any, error) {
+	return resolveContext(insideModule(ctx, v.path), v.via, v.qualify(name))
+}
+
+// ResolveReady resolves and starts a service by its name as seen from the module.
+func (v *moduleView) ResolveReady(ctx context.Context, name string) (any, error) {
+	return v.via.ResolveReady(insideModule(ctx, v.path), v.qualify(name))
+}
+
+// Has checks if a service is visible from the module.
+func (v *moduleView) Has(name string) bool {
+	qualified := v.qualify(name)
+	if qualified != name {
+		// The module's own and its ancestors' services are always visible
+		return v.root.registered(qualified)
+	}
+
+	return v.via.Has(name)
+}
+
+// IsStarted checks if a service visible from the module has been started.
+func (v *moduleView) IsStarted(name string) bool {
+	return v.root.IsStarted(v.qualify(name))
+}
+
+// Services returns all registered service names in the container.
+func (v *moduleView) Services() []string {
+	return v.root.Services()
+}
+
+// BeginScope creates a new scope on the container.
+func (v *moduleView) BeginScope() Scope {
+	return v.root.BeginScope()
+}
+
+// Start starts the container.
+func (v *moduleView) Start(ctx context.Context) error {
+	return v.root.Start(ctx)
+}
+
+// Stop stops the container.
+func (v *moduleView) Stop(ctx context.Context) error {
+	return v.root.Stop(ctx)
+}
+
+// Health checks the container.
+func (v *moduleView) Health(ctx context.Context) error {
+	return v.root.Health(ctx)
+}
+
+// Inspect returns diagnostic information about a service visible from the module.
+func (v *moduleView) Inspect(name string) ServiceInfo {
+	return v.root.Inspect(v.qualify(name))
+}
+
+// alias registers name as a second name for target's registration.
+// Both names share one instance; the alias depends on the target so
+// start and stop order is preserved.
+func (c *containerImpl) alias(name, target string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reg, exists := c.services[target]
+	if !exists {
+		return ErrServiceNotFound(target)
+	}
+
+	if _, exists := c.services[name]; exists {
+		return ErrServiceAlreadyExists(name)
+	}
+
+	c.services[name] = reg
+	c.graph.AddNode(name, []string{target})
+
+	return nil
+}
+
+// setPrivate makes the service registered under name visible only from the
+// module at path and the modules nested in it, or from anywhere when path
+// is empty. Aliases share the setting, as they share the registration.
+func (c *containerImpl) setPrivate(name, path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if reg, exists := c.services[name]; exists {
+		reg.private = path
+	}
+
+	if path != "" {
+		c.hasPrivate.Store(true)
+	}
+}
+
+// registered reports whether name is registered in the container or its
+// ancestors, private or not.
+func (c *containerImpl) registered(name string) bool {
+	_, _, exists := c.lookup(name)
+
+	return exists
+}
+
+// hidden reports whether name is a private service of a module that ctx
+// doesn't come from. Resolving it anywhere but inside that module, or a
+// module nested in it, fails as if it wasn't registered.
+func (c *containerImpl) hidden(ctx context.Context, name string) bool {
+	if !c.hasPrivate.Load() {
+		return false
+	}
+
+	c.mu.RLock()
+
+	var private string
+	if reg, exists := c.services[name]; exists {
+		private = reg.private
+	}
+
+	c.mu.RUnlock()
+
+	if private == "" {
+		return false
+	}
+
+	caller, _ := ctx.Value(moduleKey{}).(string)
+
+	return caller != private && !strings.HasPrefix(caller, private+".")
+}
+
+// moduleKey is the context key for the module a resolution comes from.
+type moduleKey struct{}
+
+// insideModule marks ctx as coming from the module at path.
+func insideModule(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, moduleKey{}, path)
+}
+
+// outsideModule drops the module mark from ctx, so it doesn't carry over to
+// the factories a resolution runs.
+func outsideModule(ctx context.Context) context.Context {
+	if ctx.Value(moduleKey{}) == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, moduleKey{}, nil)
+}
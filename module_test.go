@@ -0,0 +1,233 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule_PrivateAndExported(t *testing.T) {
+	c := New()
+
+	billing := Module("billing",
+		Service("repo", func(c Vessel) (any, error) {
+			return &testService{value: "billing-repo"}, nil
+		}),
+		Service("service", func(c Vessel) (any, error) {
+			repo, err := Resolve[*testService](c, "repo")
+			if err != nil {
+				return nil, err
+			}
+
+			return &testService{value: "service using " + repo.value}, nil
+		}, WithDependencies("repo")),
+		Export("service"),
+	)
+
+	require.NoError(t, Install(c, billing))
+
+	assert.False(t, c.Has("repo"), "private services are not visible by short name")
+	assert.False(t, c.Has("billing.repo"), "nor by their qualified name")
+	assert.True(t, c.Has("service"))
+	assert.NotContains(t, c.Services(), "billing.repo")
+
+	svc := Must[*testService](c, "service")
+	assert.Equal(t, "service using billing-repo", svc.value)
+
+	// Exported name shares the module's instance
+	assert.Same(t, svc, Must[*testService](c, "billing.service"))
+
+	_, err := c.Resolve("billing.repo")
+	assert.ErrorIs(t, err, ErrServiceNotFound("billing.repo"))
+
+	info := c.Inspect("billing.service")
+	assert.Equal(t, "billing", info.Metadata["__module"])
+	assert.Equal(t, []string{"billing.repo"}, info.Dependencies)
+}
+
+func TestModule_SameNamesInDifferentModules(t *testing.T) {
+	c := New()
+
+	newModule := func(name string) *ModuleDef {
+		return Module(name,
+			Service("repo", func(c Vessel) (any, error) {
+				return &testService{value: name}, nil
+			}),
+			Setup(func(m Vessel) error {
+				return Provide[*testService](m, "api",
+					Inject[*testService]("repo"),
+					func(repo *testService) (*testService, error) {
+						return &testService{value: "api:" + repo.value}, nil
+					},
+				)
+			}),
+			Export("api"),
+		)
+	}
+
+	require.NoError(t, Install(c, newModule("users")))
+
+	// Exporting the same name twice collides, as a flat registration would
+	err := Install(c, newModule("orders"))
+	assert.ErrorIs(t, err, ErrServiceAlreadyExists("api"))
+
+	// Private names never collide
+	assert.Equal(t, "users", c.Inspect("users.repo").Metadata["__module"])
+	assert.Equal(t, "orders", c.Inspect("orders.repo").Metadata["__module"])
+	assert.Equal(t, "api:users", Must[*testService](c, "api").value)
+
+	// orders.api was never exported, so it stays private
+	_, err = c.Resolve("orders.api")
+	assert.ErrorIs(t, err, ErrServiceNotFound("orders.api"))
+}
+
+func TestModule_Nested(t *testing.T) {
+	c := New()
+
+	app := Module("app",
+		Service("config", func(c Vessel) (any, error) {
+			return &testService{value: "app-config"}, nil
+		}),
+		Module("db",
+			// Nested modules see their parent's private services
+			Service("pool", func(c Vessel) (any, error) {
+				cfg, err := Resolve[*testService](c, "config")
+				if err != nil {
+					return nil, err
+				}
+
+				return &testService{value: "pool with " + cfg.value}, nil
+			}),
+			Export("pool"),
+		),
+		Export("pool"),
+	)
+
+	require.NoError(t, Install(c, app))
+
+	assert.True(t, c.Has("pool"))
+	assert.True(t, c.Has("app.pool"))
+	assert.True(t, c.Has("app.db.pool"))
+	assert.False(t, c.Has("config"))
+
+	pool := Must[*testService](c, "pool")
+	assert.Equal(t, "pool with app-config", pool.value)
+	assert.Same(t, pool, Must[*testService](c, "app.db.pool"))
+
+	names := QueryNames(c, ServiceQuery{Module: "app.db"})
+	assert.ElementsMatch(t, []string{"app.db.pool", "app.pool", "pool"}, names)
+	assert.Empty(t, FindByModule(c, "app"), "app.config is private")
+}
+
+func TestModule_StartOrder(t *testing.T) {
+	c := New()
+	order := []string{}
+
+	track := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart:     func() { order = append(order, name) },
+			}, nil
+		}
+	}
+
+	// Declared before its dependency to check ordering across the module
+	require.NoError(t, Install(c, Module("m",
+		Service("api", track("api"), WithDependencies("store")),
+		Service("store", track("store")),
+		Export("api"),
+	)))
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"store", "api"}, order)
+
+	require.NoError(t, c.Stop(context.Background()))
+}
+
+func TestModule_Errors(t *testing.T) {
+	c := New()
+
+	err := Install(c, Module("m", Export("missing")))
+	assert.ErrorContains(t, err, "exports unknown service missing")
+
+	err = Install(c, Module(""))
+	assert.Error(t, err)
+
+	setupErr := errors.New("setup failed")
+	err = Install(c, Module("bad", Setup(func(m Vessel) error { return setupErr })))
+	assert.ErrorIs(t, err, setupErr)
+
+	// Registering through a module view after install is rejected
+	var captured Vessel
+	require.NoError(t, Install(c, Module("late", Setup(func(m Vessel) error {
+		captured = m
+
+		return nil
+	}))))
+
+	err = captured.Register("x", func(c Vessel) (any, error) { return nil, nil })
+	assert.ErrorContains(t, err, "already installed")
+}
+
+func TestModule_Constructor(t *testing.T) {
+	c := New()
+
+	require.NoError(t, Install(c, Module("data",
+		Constructor(newTestDatabase),
+		Constructor(newTestLogger),
+	)))
+
+	_, err := InjectType[*testDatabase](c)
+	assert.NoError(t, err)
+}
+
+func TestModule_PrivateFromFactoriesAndScopes(t *testing.T) {
+	c := New()
+
+	require.NoError(t, Install(c, Module("billing",
+		Service("repo", newServiceFactory("repo")),
+		Module("invoices",
+			// Nested modules see their parent's private services
+			Service("store", resolving("store", "repo")),
+			Export("store"),
+		),
+	)))
+
+	// Starting the container still reaches every service
+	require.NoError(t, c.Start(context.Background()))
+	assert.True(t, c.IsStarted("billing.invoices.store"))
+
+	// Exported to billing only, so still private outside it
+	_, err := c.Resolve("billing.store")
+	assert.ErrorIs(t, err, ErrServiceNotFound("billing.store"))
+
+	// A factory outside the module can't reach into it
+	require.NoError(t, c.Register("spy", resolving("spy", "billing.repo"), Transient()))
+
+	_, err = c.Resolve("spy")
+	assert.ErrorIs(t, err, ErrServiceNotFound("billing.repo"))
+}
+
+func TestModule_PrivateScopedService(t *testing.T) {
+	c := New()
+
+	require.NoError(t, Install(c, Module("billing",
+		Service("session", newServiceFactory("session"), Scoped()),
+		Service("cart", resolving("cart", "session"), Scoped()),
+		Export("cart"),
+	)))
+
+	scope := c.BeginScope()
+	defer scope.End()
+
+	_, err := scope.Resolve("billing.session")
+	assert.ErrorIs(t, err, ErrServiceNotFound("billing.session"))
+
+	// The module's own scoped services still reach it
+	_, err = scope.Resolve("cart")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,329 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ContainerSnapshot captures a container's registrations so they can be
+// put back with Restore after tests or environment-specific setup have
+// overridden them.
+type ContainerSnapshot struct {
+	owner    *containerImpl
+	services map[string]*serviceRegistration
+	graph    *DependencyGraph
+	types    *typeRegistry
+}
+
+// Replace registers a service under name, overwriting any existing
+// registration with that name instead of failing with ErrServiceAlreadyExists.
+// The dependency graph is updated to the new registration's dependencies and
+// the service keeps its original position in the start order.
+//
+// A cached instance of the replaced registration is stopped together with
+// its lifecycle hooks, then disposed or closed as by Close. Services that
+// depend on it still hold that instance, so they are stopped, released and
+// rebuilt as by Restart; while the container is running they are started
+// again. If anything fails to stop, the registration is left in place.
+// A module's private service stays private. Decorators added with Decorate
+// belong to the replaced registration and are dropped with it; decorate
+// the replacement again if it needs them.
+func (c *containerImpl) Replace(name string, factory Factory, opts ...RegisterOption) error {
+	if factory == nil {
+		return ErrInvalidFactory
+	}
+
+	ctx := context.Background()
+
+	c.mu.RLock()
+	old, exists := c.services[name]
+
+	// A module alias shares its target's registration; only the name goes
+	if exists && old.name != name {
+		old = nil
+	}
+
+	var (
+		affected []string
+		private  string
+		sortErr  error
+	)
+
+	if old != nil {
+		var order []string
+
+		order, sortErr = c.graph.TopologicalSort()
+		affected = c.graph.dependents(name, order)
+		private = old.private
+
+		if len(affected) == 0 {
+			affected = []string{name}
+		}
+	}
+
+	running := c.started
+	c.mu.RUnlock()
+
+	if sortErr != nil {
+		return sortErr
+	}
+
+	// Dependents first, as with Stop
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.stopService(ctx, affected[i]); err != nil {
+			return NewServiceError(affected[i], "stop", err)
+		}
+	}
+
+	if err := c.register(name, factory, nil, opts, true); err != nil {
+		return err
+	}
+
+	if old == nil {
+		return nil
+	}
+
+	if private != "" {
+		c.setPrivate(name, private)
+	}
+
+	// Snapshots may still hold the old registration; restoring it rebuilds
+	old.mu.Lock()
+	instances := []any{old.instance}
+	old.instance = nil
+	old.started = false
+	old.mu.Unlock()
+
+	c.lifecycle.forget(name)
+
+	for _, svc := range affected[1:] {
+		instances = append(instances, c.discard(svc))
+	}
+
+	var replaceErrs []error
+
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.release(ctx, instances[i]); err != nil {
+			replaceErrs = append(replaceErrs, NewServiceError(affected[i], "close", err))
+		}
+	}
+
+	if running {
+		for _, svc := range affected {
+			if err := c.startService(ctx, svc); err != nil {
+				replaceErrs = append(replaceErrs, NewServiceError(svc, "start", err))
+
+				break
+			}
+		}
+	}
+
+	return errors.Join(replaceErrs...)
+}
+
+// Snapshot captures the container's current registrations.
+// Instances created after the snapshot stay cached on the registrations
+// that were already present when it was taken.
+func (c *containerImpl) Snapshot() *ContainerSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	services := make(map[string]*serviceRegistration, len(c.services))
+	for name, reg := range c.services {
+		services[name] = reg
+	}
+
+	return &ContainerSnapshot{
+		owner:    c,
+		services: services,
+		graph:    c.graph.clone(),
+		types:    c.typeRegistry.snapshot(),
+	}
+}
+
+// Restore puts the container's registrations back to the state captured by
+// snapshot. Services registered or replaced since then are dropped.
+// A snapshot can be restored any number of times.
+//
+// Dropped services, and the services depending on them, are stopped and
+// then disposed or closed as by Close; while the container is running, the
+// ones left afterwards are started again. Dropped type-based singletons are
+// released the same way. Every service is handled even if others fail; the
+// returned error joins the failures.
+func (c *containerImpl) Restore(snapshot *ContainerSnapshot) error {
+	if snapshot == nil {
+		return errors.New("snapshot cannot be nil")
+	}
+
+	if snapshot.owner != c {
+		return errors.New("snapshot was taken from a different container")
+	}
+
+	ctx := context.Background()
+	affected := c.restoreAffected(snapshot)
+
+	var restoreErrs []error
+
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.stopService(ctx, affected[i]); err != nil {
+			restoreErrs = append(restoreErrs, NewServiceError(affected[i], "stop", err))
+		}
+	}
+
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.release(ctx, c.discard(affected[i])); err != nil {
+			restoreErrs = append(restoreErrs, NewServiceError(affected[i], "close", err))
+		}
+	}
+
+	types := c.typeRegistry.snapshot()
+
+	c.mu.Lock()
+
+	services := make(map[string]*serviceRegistration, len(snapshot.services))
+	for name, reg := range snapshot.services {
+		services[name] = reg
+	}
+
+	c.services = services
+	c.graph = snapshot.graph.clone()
+	c.typeRegistry.restore(snapshot.types)
+	running := c.started
+
+	c.mu.Unlock()
+
+	restoreErrs = append(restoreErrs, c.releaseTypes(ctx, types.registrations())...)
+
+	if running {
+		for _, name := range affected {
+			if err := c.startService(ctx, name); err != nil {
+				restoreErrs = append(restoreErrs, NewServiceError(name, "start", err))
+
+				break
+			}
+		}
+	}
+
+	return errors.Join(restoreErrs...)
+}
+
+// restoreAffected returns the services Restore drops and those depending
+// on them, in dependency order, without module aliases.
+func (c *containerImpl) restoreAffected(snapshot *ContainerSnapshot) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	order, err := c.graph.TopologicalSort()
+	if err != nil {
+		order = append([]string(nil), c.graph.order...)
+	}
+
+	affected := make(map[string]bool)
+
+	for name, reg := range c.services {
+		if snapshot.services[name] == reg {
+			continue
+		}
+
+		for _, dependent := range c.graph.dependents(name, order) {
+			affected[dependent] = true
+		}
+	}
+
+	names := make([]string, 0, len(affected))
+
+	for _, name := range order {
+		if reg, exists := c.services[name]; exists && affected[name] && reg.name == name {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// releaseTypes stops the lifecycle hooks of the type-based singletons among
+// regs that are no longer registered, then disposes or closes them, most
+// recently created first.
+func (c *containerImpl) releaseTypes(ctx context.Context, regs []*typeRegistration) []error {
+	seqs := make(map[*typeRegistration]uint64, len(regs))
+
+	for _, reg := range regs {
+		reg.mu.RLock()
+		seqs[reg] = reg.createdSeq
+		reg.mu.RUnlock()
+	}
+
+	sort.Slice(regs, func(i, j int) bool {
+		return seqs[regs[i]] > seqs[regs[j]]
+	})
+
+	var releaseErrs []error
+
+	for _, reg := range regs {
+		if c.typeRegistry.holds(reg) {
+			continue
+		}
+
+		hookErr := c.stopHooks(ctx, func(owner any) bool { return owner == reg.key })
+		c.lifecycle.forget(reg.key)
+
+		reg.mu.Lock()
+		instance := reg.instance
+		reg.instance = nil
+		reg.mu.Unlock()
+
+		if err := errors.Join(hookErr, c.release(ctx, instance)); err != nil {
+			releaseErrs = append(releaseErrs, NewServiceError(reg.key.String(), "close", err))
+		}
+	}
+
+	return releaseErrs
+}
+
+// Replace registers a service, overwriting any existing registration with
+// the same name.
+//
+// Example:
+//
+//	// Swap the real database for a fake after normal wiring
+//	vessel.Replace(c, "database", func(c vessel.Vessel) (any, error) {
+//	    return fakeDB, nil
+//	})
+func Replace(c Vessel, name string, factory Factory, opts ...RegisterOption) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Replace requires *containerImpl, got %T", c)
+	}
+
+	return impl.Replace(name, factory, opts...)
+}
+
+// Snapshot captures the container's registrations for a later Restore.
+//
+// Example:
+//
+//	snap, _ := vessel.Snapshot(c)
+//	defer vessel.Restore(c, snap)
+//
+//	vessel.Replace(c, "mailer", fakeMailerFactory)
+func Snapshot(c Vessel) (*ContainerSnapshot, error) {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return nil, fmt.Errorf("Snapshot requires *containerImpl, got %T", c)
+	}
+
+	return impl.Snapshot(), nil
+}
+
+// Restore puts the container's registrations back to a snapshot taken
+// from the same container.
+func Restore(c Vessel, snapshot *ContainerSnapshot) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Restore requires *containerImpl, got %T", c)
+	}
+
+	return impl.Restore(snapshot)
+}
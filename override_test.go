@@ -0,0 +1,305 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplace_OverridesExisting(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterValue(c, "db", &testService{value: "real"}))
+
+	// Resolve once so a cached instance exists
+	_, err := c.Resolve("db")
+	require.NoError(t, err)
+
+	err = Replace(c, "db", func(c Vessel) (any, error) {
+		return &testService{value: "fake"}, nil
+	})
+	require.NoError(t, err)
+
+	db := Must[*testService](c, "db")
+	assert.Equal(t, "fake", db.value)
+	assert.Len(t, c.Services(), 1)
+}
+
+func TestReplace_RegistersWhenMissing(t *testing.T) {
+	c := New()
+
+	err := Replace(c, "cache", func(c Vessel) (any, error) {
+		return "cache", nil
+	})
+	require.NoError(t, err)
+	assert.True(t, c.Has("cache"))
+
+	assert.ErrorIs(t, Replace(c, "cache", nil), ErrInvalidFactory)
+}
+
+func TestReplace_UpdatesDependencyGraph(t *testing.T) {
+	c := New()
+	order := []string{}
+
+	track := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart:     func() { order = append(order, name) },
+			}, nil
+		}
+	}
+
+	require.NoError(t, c.Register("api", track("api"), WithDependencies("db")))
+	require.NoError(t, c.Register("db", track("db")))
+	require.NoError(t, c.Register("cache", track("cache")))
+
+	// The replacement depends on cache instead of db
+	require.NoError(t, Replace(c, "api", track("api"), WithDependencies("cache")))
+
+	impl := c.(*containerImpl)
+	assert.Equal(t, []string{"cache"}, impl.graph.GetDependencies("api"))
+	assert.Len(t, impl.graph.order, 3)
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"cache", "api", "db"}, order)
+}
+
+func TestReplace_StopsAndReleasesStartedInstance(t *testing.T) {
+	c := New()
+	old := &mockService{name: "db"}
+	require.NoError(t, RegisterValue(c, "db", old))
+	require.NoError(t, Decorate(c, "db", func(c Vessel, svc *mockService) (*mockService, error) {
+		svc.name = "decorated"
+
+		return svc, nil
+	}))
+	require.NoError(t, c.Start(context.Background()))
+
+	replacement := &mockService{name: "fake"}
+	require.NoError(t, Replace(c, "db", func(c Vessel) (any, error) {
+		return replacement, nil
+	}))
+
+	assert.True(t, old.stopped)
+	assert.True(t, old.disposed)
+
+	// Decorators belonged to the replaced registration
+	db := Must[*mockService](c, "db")
+	assert.Same(t, replacement, db)
+	assert.Equal(t, "fake", db.name)
+}
+
+func TestReplace_StopFailureKeepsRegistration(t *testing.T) {
+	c := New()
+	stopErr := errors.New("stop failed")
+	old := &mockService{name: "db", stopErr: stopErr}
+	require.NoError(t, RegisterValue(c, "db", old))
+	require.NoError(t, c.Start(context.Background()))
+
+	err := Replace(c, "db", func(c Vessel) (any, error) {
+		return &mockService{name: "fake"}, nil
+	})
+	require.ErrorIs(t, err, stopErr)
+
+	assert.Same(t, old, Must[*mockService](c, "db"))
+	assert.False(t, old.disposed)
+}
+
+// dbUser returns a factory for a service that resolves "db" and records
+// the instance it got in used.
+func dbUser(name string, used *[]*mockService) Factory {
+	return func(c Vessel) (any, error) {
+		db, err := Resolve[*mockService](c, "db")
+		if err != nil {
+			return nil, err
+		}
+
+		*used = append(*used, db)
+
+		return &mockService{name: name}, nil
+	}
+}
+
+func TestReplace_RebuildsDependents(t *testing.T) {
+	c := New()
+	var used []*mockService
+
+	old := &mockService{name: "db"}
+	require.NoError(t, RegisterValue(c, "db", old))
+	require.NoError(t, c.Register("repo", dbUser("repo", &used), WithDependencies("db")))
+	require.NoError(t, c.Start(context.Background()))
+
+	oldRepo := Must[*mockService](c, "repo")
+	fake := &mockService{name: "fake"}
+
+	require.NoError(t, Replace(c, "db", func(c Vessel) (any, error) {
+		return fake, nil
+	}))
+
+	// The repo holding the closed database was released and rebuilt
+	assert.True(t, old.disposed)
+	assert.True(t, oldRepo.stopped)
+	assert.True(t, oldRepo.disposed)
+	assert.Equal(t, []*mockService{old, fake}, used)
+	assert.True(t, c.IsStarted("repo"))
+	assert.NotSame(t, oldRepo, Must[*mockService](c, "repo"))
+}
+
+func TestReplace_KeepsModuleServicePrivate(t *testing.T) {
+	c := New()
+	require.NoError(t, Install(c, Module("billing",
+		Service("repo", newServiceFactory("repo")),
+	)))
+
+	require.NoError(t, Replace(c, "billing.repo", newServiceFactory("fake")))
+
+	assert.False(t, c.Has("billing.repo"))
+	assert.NotContains(t, c.Services(), "billing.repo")
+}
+
+func TestSnapshotRestore_RevertsOverrides(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterValue(c, "db", &testService{value: "real"}))
+
+	snap, err := Snapshot(c)
+	require.NoError(t, err)
+
+	require.NoError(t, Replace(c, "db", func(c Vessel) (any, error) {
+		return &testService{value: "fake"}, nil
+	}))
+	require.NoError(t, RegisterValue(c, "extra", "added after snapshot"))
+
+	assert.Equal(t, "fake", Must[*testService](c, "db").value)
+
+	require.NoError(t, Restore(c, snap))
+
+	assert.Equal(t, "real", Must[*testService](c, "db").value)
+	assert.False(t, c.Has("extra"))
+
+	// The snapshot can be reused
+	require.NoError(t, RegisterValue(c, "extra", "again"))
+	require.NoError(t, Restore(c, snap))
+	assert.False(t, c.Has("extra"))
+}
+
+func TestRestore_StopsAndReleasesDroppedServices(t *testing.T) {
+	c := New()
+	var used []*mockService
+
+	original := &mockService{name: "db"}
+	require.NoError(t, RegisterValue(c, "db", original))
+	require.NoError(t, c.Register("repo", dbUser("repo", &used), WithDependencies("db")))
+
+	snap, err := Snapshot(c)
+	require.NoError(t, err)
+
+	fake := &mockService{name: "fake"}
+	require.NoError(t, Replace(c, "db", func(c Vessel) (any, error) { return fake, nil }))
+
+	extra := &mockService{name: "extra"}
+	require.NoError(t, RegisterValue(c, "extra", extra))
+	require.NoError(t, c.Start(context.Background()))
+
+	require.NoError(t, Restore(c, snap))
+
+	assert.True(t, extra.stopped)
+	assert.True(t, extra.disposed)
+	assert.True(t, fake.stopped)
+	assert.True(t, fake.disposed)
+
+	// The repo built with the fake is rebuilt with the restored database
+	assert.Equal(t, []*mockService{fake, original}, used)
+	assert.True(t, c.IsStarted("repo"))
+}
+
+func TestRestore_RejectsForeignSnapshot(t *testing.T) {
+	c1 := New()
+	c2 := New()
+
+	snap, err := Snapshot(c1)
+	require.NoError(t, err)
+
+	assert.Error(t, Restore(c2, snap))
+	assert.Error(t, Restore(c1, nil))
+}
+
+func TestReplaceConstructor(t *testing.T) {
+	c := New()
+	require.NoError(t, ProvideConstructor(c, newTestDatabase))
+	require.NoError(t, ProvideConstructor(c, newTestLogger))
+	require.NoError(t, ProvideConstructor(c, newTestUserService))
+
+	// Plain ProvideConstructor still refuses duplicates
+	assert.Error(t, ProvideConstructor(c, newTestDatabase))
+
+	fake := &testDatabase{connStr: "fake"}
+	require.NoError(t, ReplaceConstructor(c, func() *testDatabase { return fake }))
+
+	svc, err := InjectType[*testUserService](c)
+	require.NoError(t, err)
+	assert.Same(t, fake, svc.db)
+}
+
+func TestReplaceConstructor_ReleasesCachedSingleton(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, ProvideConstructor(c, func() *recordingCloser {
+		return &recordingCloser{name: "old", events: &events}
+	}))
+
+	_, err := InjectType[*recordingCloser](c)
+	require.NoError(t, err)
+
+	require.NoError(t, ReplaceConstructor(c, func() *recordingCloser {
+		return &recordingCloser{name: "new", events: &events}
+	}))
+	assert.Equal(t, []string{"close old"}, events)
+
+	replaced, err := InjectType[*recordingCloser](c)
+	require.NoError(t, err)
+	assert.Equal(t, "new", replaced.name)
+}
+
+func TestReplaceConstructor_Groups(t *testing.T) {
+	c := New()
+	require.NoError(t, ProvideConstructor(c, func() *testCache {
+		return &testCache{host: "original"}
+	}, AsGroup("caches")))
+
+	require.NoError(t, ReplaceConstructor(c, func() *testCache {
+		return &testCache{host: "replacement"}
+	}, AsGroup("caches")))
+
+	caches, err := InjectGroup[*testCache](c, "caches")
+	require.NoError(t, err)
+	require.Len(t, caches, 1)
+	assert.Equal(t, "replacement", caches[0].host)
+}
+
+func TestSnapshotRestore_TypeRegistry(t *testing.T) {
+	c := New()
+	original := &testDatabase{connStr: "real"}
+	require.NoError(t, ProvideConstructor(c, func() *testDatabase { return original }))
+
+	snap, err := Snapshot(c)
+	require.NoError(t, err)
+
+	fake := &testDatabase{connStr: "fake"}
+	require.NoError(t, ReplaceConstructor(c, func() *testDatabase { return fake }))
+	require.NoError(t, ProvideConstructor(c, newTestLogger))
+
+	db, err := InjectType[*testDatabase](c)
+	require.NoError(t, err)
+	assert.Same(t, fake, db)
+
+	require.NoError(t, Restore(c, snap))
+
+	db, err = InjectType[*testDatabase](c)
+	require.NoError(t, err)
+	assert.Same(t, original, db)
+	assert.False(t, HasType[*testLogger](c))
+}
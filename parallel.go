@@ -0,0 +1,103 @@
+package vessel
+
+import "context"
+
+// startResult reports the outcome of starting one service.
+type startResult struct {
+	name string
+	err  error
+}
+
+// startParallel starts services concurrently, launching each one as soon as
+// every dependency in deps has started. order must be a topological order of
+// the services; it decides which of several ready services goes first.
+// On failure no new services are launched, in-flight starts are awaited and
+// everything already running is stopped again in reverse order.
+func (c *containerImpl) startParallel(ctx context.Context, order []string, deps map[string][]string) error {
+	if len(order) == 0 {
+		return nil
+	}
+
+	// Count unmet dependencies and index dependents for release
+	pending := make(map[string]int, len(order))
+	dependents := make(map[string][]string, len(order))
+
+	for _, name := range order {
+		for _, dep := range deps[name] {
+			pending[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	ready := make([]string, 0, len(order))
+	for _, name := range order {
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	workers := c.options.startWorkers
+	if workers <= 0 || workers > len(order) {
+		workers = len(order)
+	}
+
+	results := make(chan startResult)
+	running := 0
+
+	var (
+		failed   string
+		firstErr error
+	)
+
+	for len(ready) > 0 || running > 0 {
+		// Launch ready services up to the worker limit
+		for firstErr == nil && running < workers && len(ready) > 0 {
+			name := ready[0]
+
+			if err := ctx.Err(); err != nil {
+				failed, firstErr = name, err
+
+				break
+			}
+
+			ready = ready[1:]
+			running++
+
+			go func() {
+				results <- startResult{name: name, err: c.startService(ctx, name)}
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+
+		if res.err != nil {
+			if firstErr == nil {
+				failed, firstErr = res.name, res.err
+			}
+
+			continue
+		}
+
+		// Release dependents whose dependencies are now all running
+		for _, dependent := range dependents[res.name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if firstErr != nil {
+		// Rollback: stop already started services
+		c.stopServices(ctx, order)
+
+		return NewServiceError(failed, "start", firstErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xraph/go-utils/errs"
+)
+
+func TestParallelStart_IndependentServicesStartConcurrently(t *testing.T) {
+	c := New(WithParallelStart(0))
+
+	// Each service blocks until both have entered Start, which only
+	// completes if they run at the same time.
+	var entered sync.WaitGroup
+	entered.Add(2)
+
+	for _, name := range []string{"a", "b"} {
+		err := c.Register(name, func(c Vessel) (any, error) {
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart: func() {
+					entered.Done()
+					entered.Wait()
+				},
+			}, nil
+		})
+		require.NoError(t, err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- c.Start(context.Background()) }()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("independent services were not started concurrently")
+	}
+
+	assert.True(t, c.IsStarted("a"))
+	assert.True(t, c.IsStarted("b"))
+}
+
+func TestParallelStart_RespectsDependencies(t *testing.T) {
+	c := New(WithParallelStart(0))
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	register := func(name string, deps ...string) {
+		err := c.Register(name, func(c Vessel) (any, error) {
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart: func() {
+					time.Sleep(5 * time.Millisecond)
+					mu.Lock()
+					order = append(order, name)
+					mu.Unlock()
+				},
+			}, nil
+		}, WithDependencies(deps...))
+		require.NoError(t, err)
+	}
+
+	register("config")
+	register("db", "config")
+	register("cache", "config")
+	register("api", "db", "cache")
+
+	require.NoError(t, c.Start(context.Background()))
+	require.Len(t, order, 4)
+
+	assert.Equal(t, "config", order[0])
+	assert.Equal(t, "api", order[3])
+	assert.ElementsMatch(t, []string{"db", "cache"}, order[1:3])
+}
+
+func TestParallelStart_WorkerLimit(t *testing.T) {
+	c := New(WithParallelStart(2))
+
+	var current, peak int32
+
+	for _, name := range []string{"s1", "s2", "s3", "s4", "s5", "s6"} {
+		err := c.Register(name, func(c Vessel) (any, error) {
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart: func() {
+					n := atomic.AddInt32(&current, 1)
+					for {
+						p := atomic.LoadInt32(&peak)
+						if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&current, -1)
+				},
+			}, nil
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, c.Start(context.Background()))
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+}
+
+func TestParallelStart_FailureRollsBack(t *testing.T) {
+	c := New(WithParallelStart(0))
+
+	base := &mockService{name: "base"}
+	sibling := &mockService{name: "sibling"}
+	broken := &mockService{name: "broken", startErr: errors.New("connect refused")}
+
+	var dependentStarted atomic.Bool
+
+	require.NoError(t, c.Register("base", func(c Vessel) (any, error) {
+		return base, nil
+	}))
+	require.NoError(t, c.Register("sibling", func(c Vessel) (any, error) {
+		return sibling, nil
+	}, WithDependencies("base")))
+	require.NoError(t, c.Register("broken", func(c Vessel) (any, error) {
+		return broken, nil
+	}, WithDependencies("base")))
+	require.NoError(t, c.Register("dependent", func(c Vessel) (any, error) {
+		return &mockServiceWithCallback{
+			mockService: mockService{name: "dependent"},
+			onStart:     func() { dependentStarted.Store(true) },
+		}, nil
+	}, WithDependencies("broken")))
+
+	err := c.Start(context.Background())
+	require.Error(t, err)
+
+	var serviceErr *errs.Error
+	require.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, "broken", serviceErr.GetContext()["service"])
+
+	assert.False(t, dependentStarted.Load(), "dependent of a failed service must not start")
+	assert.True(t, base.stopped, "started services should be rolled back")
+	assert.True(t, sibling.stopped, "started services should be rolled back")
+	assert.False(t, c.IsStarted("base"))
+}
+
+func TestParallelStart_CancelledContext(t *testing.T) {
+	c := New(WithParallelStart(0))
+
+	require.NoError(t, c.Register("svc", func(c Vessel) (any, error) {
+		return &mockService{name: "svc"}, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Start(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, c.IsStarted("svc"))
+}
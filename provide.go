@@ -128,6 +128,10 @@ func ProvideWithOpts[T any](c Vessel, name string, opts []di.RegisterOption, arg
 
 // resolveDep resolves a single dependency based on its mode.
 func resolveDep(c Vessel, opt InjectOption) (any, error) {
+	if opt.fromScope {
+		return resolveScopeValue(c, opt)
+	}
+
 	switch opt.Dep.Mode {
 	case di.DepEager:
 		// Resolve immediately, fail if not found
@@ -154,6 +158,25 @@ func resolveDep(c Vessel, opt InjectOption) (any, error) {
 	}
 }
 
+// resolveScopeValue reads a FromScope value from the scope c is bound to.
+func resolveScopeValue(c Vessel, opt InjectOption) (any, error) {
+	s, ok := ScopeOf(c)
+	if !ok {
+		return nil, fmt.Errorf("scope value %s: %w", opt.Dep.Name, ErrNoScope)
+	}
+
+	value, ok := s.(*scope).Get(opt.Dep.Name)
+	if !ok {
+		return nil, fmt.Errorf("scope value %s not set", opt.Dep.Name)
+	}
+
+	if value == nil || !reflect.TypeOf(value).AssignableTo(opt.TypeInfo) {
+		return nil, fmt.Errorf("scope value %s: type mismatch, expected %s but got %T", opt.Dep.Name, opt.TypeInfo, value)
+	}
+
+	return value, nil
+}
+
 // createLazyWrapper creates a Lazy[T] wrapper for the dependency.
 // Since we can't use generics dynamically, we return a LazyAny wrapper.
 func createLazyWrapper(c Vessel, opt InjectOption) (*LazyAny, error) {
@@ -1,6 +1,8 @@
 package vessel
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -169,6 +171,26 @@ func WithEager() ConstructorOption {
 //	}
 //	ProvideConstructor(c, NewService)
 func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) error {
+	return provideConstructor(c, constructor, opts, false)
+}
+
+// ReplaceConstructor registers a constructor like ProvideConstructor, but
+// overwrites any existing registration for the same result types, names,
+// aliases and interface types instead of failing. Cached singletons of the
+// replaced registrations have their lifecycle hooks stopped and are then
+// disposed or closed as by Close.
+//
+// Example:
+//
+//	// Swap the real database for a fake in tests
+//	vessel.ReplaceConstructor(c, func() *Database { return fakeDB })
+func ReplaceConstructor(c Vessel, constructor any, opts ...ConstructorOption) error {
+	return provideConstructor(c, constructor, opts, true)
+}
+
+// provideConstructor registers every result of constructor, overwriting
+// existing registrations when replace is set.
+func provideConstructor(c Vessel, constructor any, opts []ConstructorOption, replace bool) error {
 	// Analyze the constructor
 	info, err := analyzeConstructor(constructor)
 	if err != nil {
@@ -184,7 +206,7 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 	}
 
 	// Get the container implementation
-	impl, ok := c.(*containerImpl)
+	impl, ok := containerOf(c).(*containerImpl)
 	if !ok {
 		return fmt.Errorf("ProvideConstructor requires *containerImpl, got %T", c)
 	}
@@ -197,6 +219,21 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 	// Create factory function that auto-resolves dependencies
 	factory := createAutoResolveFactory(info, impl)
 
+	add := impl.typeRegistry.register
+
+	// Registrations replaced along the way are released once nothing
+	// points at them
+	var replaced []*typeRegistration
+	if replace {
+		add = func(key typeKey, reg *typeRegistration) error {
+			if old, ok := impl.typeRegistry.get(key); ok && old != reg {
+				replaced = append(replaced, old)
+			}
+
+			return impl.typeRegistry.replace(key, reg)
+		}
+	}
+
 	// Register each result type
 	results := info.flattenResults()
 	for _, result := range results {
@@ -229,9 +266,10 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 			factory:     resultFactory,
 			lifecycle:   config.lifecycle,
 			groups:      groups,
+			container:   impl,
 		}
 
-		if err := impl.typeRegistry.register(key, reg); err != nil {
+		if err := add(key, reg); err != nil {
 			return err
 		}
 
@@ -244,8 +282,9 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 				factory:     resultFactory,
 				lifecycle:   config.lifecycle,
 				groups:      groups,
+				container:   impl,
 			}
-			if err := impl.typeRegistry.register(asKey, asReg); err != nil {
+			if err := add(asKey, asReg); err != nil {
 				return err
 			}
 		}
@@ -254,7 +293,7 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 		// NOTE: Aliases point to the SAME registration object to share singleton instances
 		for _, alias := range config.aliases {
 			aliasKey := typeKey{typ: result.typ, name: alias}
-			if err := impl.typeRegistry.register(aliasKey, reg); err != nil {
+			if err := add(aliasKey, reg); err != nil {
 				return fmt.Errorf("failed to register alias %q: %w", alias, err)
 			}
 
@@ -269,7 +308,7 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 					// This shouldn't happen since we just registered it above
 					return fmt.Errorf("failed to find registration for type %s", asType)
 				}
-				if err := impl.typeRegistry.register(aliasAsKey, asReg); err != nil {
+				if err := add(aliasAsKey, asReg); err != nil {
 					return fmt.Errorf("failed to register alias %q for type %s: %w", alias, asType, err)
 				}
 				_ = i // Unused but kept for clarity
@@ -286,7 +325,7 @@ func ProvideConstructor(c Vessel, constructor any, opts ...ConstructorOption) er
 		}
 	}
 
-	return nil
+	return errors.Join(impl.releaseTypes(context.Background(), replaced)...)
 }
 
 // createAutoResolveFactory creates a factory that automatically resolves
@@ -299,14 +338,14 @@ func createAutoResolveFactory(info *constructorInfo, impl *containerImpl) Factor
 		for i, param := range info.params {
 			if param.isIn {
 				// Create In struct and populate fields
-				inValue, err := resolveInStruct(param, impl)
+				inValue, err := resolveInStruct(param, impl, container)
 				if err != nil {
 					return nil, err
 				}
 				args[i] = inValue
 			} else {
 				// Resolve single parameter by type
-				resolved, err := resolveParam(param, impl)
+				resolved, err := resolveParam(param, impl, container)
 				if err != nil {
 					return nil, err
 				}
@@ -337,7 +376,7 @@ func createAutoResolveFactory(info *constructorInfo, impl *containerImpl) Factor
 }
 
 // resolveInStruct creates and populates an In struct with resolved dependencies
-func resolveInStruct(param paramInfo, impl *containerImpl) (reflect.Value, error) {
+func resolveInStruct(param paramInfo, impl *containerImpl, container Vessel) (reflect.Value, error) {
 	structType := param.typ
 	isPtr := structType.Kind() == reflect.Ptr
 	if isPtr {
@@ -352,10 +391,10 @@ func resolveInStruct(param paramInfo, impl *containerImpl) (reflect.Value, error
 
 		if field.group {
 			// Resolve group as slice
-			resolved, err = resolveGroup(field, impl)
+			resolved, err = resolveGroup(field, impl, container)
 		} else {
 			// Resolve single dependency
-			resolved, err = resolveParam(field, impl)
+			resolved, err = resolveParam(field, impl, container)
 		}
 
 		if err != nil {
@@ -380,15 +419,18 @@ func resolveInStruct(param paramInfo, impl *containerImpl) (reflect.Value, error
 	return structValue, nil
 }
 
-// resolveParam resolves a single parameter from the type registry
-func resolveParam(param paramInfo, impl *containerImpl) (any, error) {
+// resolveParam resolves a single parameter from the type registry, for a
+// constructor called with container
+func resolveParam(param paramInfo, impl *containerImpl, container Vessel) (any, error) {
 	key := typeKey{typ: param.typ, name: param.name}
 
-	// Try type registry first
-	if impl.typeRegistry != nil {
-		if reg, ok := impl.typeRegistry.get(key); ok {
-			return reg.resolve(impl)
-		}
+	if key.typ == lifecycleType && key.name == "" {
+		return impl.lifecycleFor(container), nil
+	}
+
+	// Try type registry first (falling back to parent containers)
+	if reg, ok := impl.findType(key); ok {
+		return reg.resolve(container)
 	}
 
 	// If not found and optional, return nil
@@ -400,7 +442,7 @@ func resolveParam(param paramInfo, impl *containerImpl) (any, error) {
 }
 
 // resolveGroup resolves all services in a group as a slice
-func resolveGroup(param paramInfo, impl *containerImpl) (any, error) {
+func resolveGroup(param paramInfo, impl *containerImpl, container Vessel) (any, error) {
 	if impl.typeRegistry == nil {
 		if param.optional {
 			return nil, nil
@@ -408,7 +450,7 @@ func resolveGroup(param paramInfo, impl *containerImpl) (any, error) {
 		return nil, fmt.Errorf("no providers for group %s", param.groupKey)
 	}
 
-	regs := impl.typeRegistry.getGroup(param.groupKey)
+	regs := impl.findGroup(param.groupKey)
 	if len(regs) == 0 {
 		if param.optional {
 			return nil, nil
@@ -421,7 +463,7 @@ func resolveGroup(param paramInfo, impl *containerImpl) (any, error) {
 	sliceValue := reflect.MakeSlice(param.typ, 0, len(regs))
 
 	for _, reg := range regs {
-		instance, err := reg.resolve(impl)
+		instance, err := reg.resolve(container)
 		if err != nil {
 			return nil, err
 		}
@@ -469,7 +511,7 @@ func InjectType[T any](c Vessel) (T, error) {
 	var zero T
 	t := reflect.TypeOf((*T)(nil)).Elem() // Get the type even for interfaces
 
-	impl, ok := c.(*containerImpl)
+	impl, ok := containerOf(c).(*containerImpl)
 	if !ok {
 		return zero, fmt.Errorf("InjectType requires *containerImpl, got %T", c)
 	}
@@ -479,7 +521,12 @@ func InjectType[T any](c Vessel) (T, error) {
 	}
 
 	key := typeKey{typ: t}
-	instance, err := impl.typeRegistry.resolve(key, c)
+	reg, ok := impl.findType(key)
+	if !ok {
+		return zero, fmt.Errorf("no service registered for type %s", key)
+	}
+
+	instance, err := reg.resolve(c)
 	if err != nil {
 		return zero, err
 	}
@@ -512,7 +559,7 @@ func InjectNamed[T any](c Vessel, name string) (T, error) {
 	var zero T
 	t := reflect.TypeOf((*T)(nil)).Elem() // Get the type even for interfaces
 
-	impl, ok := c.(*containerImpl)
+	impl, ok := containerOf(c).(*containerImpl)
 	if !ok {
 		return zero, fmt.Errorf("InjectNamed requires *containerImpl, got %T", c)
 	}
@@ -522,7 +569,12 @@ func InjectNamed[T any](c Vessel, name string) (T, error) {
 	}
 
 	key := typeKey{typ: t, name: name}
-	instance, err := impl.typeRegistry.resolve(key, c)
+	reg, ok := impl.findType(key)
+	if !ok {
+		return zero, fmt.Errorf("no service registered for type %s", key)
+	}
+
+	instance, err := reg.resolve(c)
 	if err != nil {
 		return zero, err
 	}
@@ -550,7 +602,7 @@ func MustInjectNamed[T any](c Vessel, name string) T {
 //
 //	handlers, err := InjectGroup[Handler](c, "http")
 func InjectGroup[T any](c Vessel, group string) ([]T, error) {
-	impl, ok := c.(*containerImpl)
+	impl, ok := containerOf(c).(*containerImpl)
 	if !ok {
 		return nil, fmt.Errorf("InjectGroup requires *containerImpl, got %T", c)
 	}
@@ -559,7 +611,7 @@ func InjectGroup[T any](c Vessel, group string) ([]T, error) {
 		return nil, fmt.Errorf("no type registry available")
 	}
 
-	regs := impl.typeRegistry.getGroup(group)
+	regs := impl.findGroup(group)
 	if len(regs) == 0 {
 		return nil, nil // Empty slice for empty groups
 	}
@@ -593,7 +645,7 @@ func MustInjectGroup[T any](c Vessel, group string) []T {
 func HasType[T any](c Vessel) bool {
 	t := reflect.TypeOf((*T)(nil)).Elem() // Get the type even for interfaces
 
-	impl, ok := c.(*containerImpl)
+	impl, ok := containerOf(c).(*containerImpl)
 	if !ok {
 		return false
 	}
@@ -602,14 +654,16 @@ func HasType[T any](c Vessel) bool {
 		return false
 	}
 
-	return impl.typeRegistry.has(typeKey{typ: t})
+	_, found := impl.findType(typeKey{typ: t})
+
+	return found
 }
 
 // HasTypeNamed checks if a named service of the given type is registered.
 func HasTypeNamed[T any](c Vessel, name string) bool {
 	t := reflect.TypeOf((*T)(nil)).Elem() // Get the type even for interfaces
 
-	impl, ok := c.(*containerImpl)
+	impl, ok := containerOf(c).(*containerImpl)
 	if !ok {
 		return false
 	}
@@ -618,5 +672,7 @@ func HasTypeNamed[T any](c Vessel, name string) bool {
 		return false
 	}
 
-	return impl.typeRegistry.has(typeKey{typ: t, name: name})
+	_, found := impl.findType(typeKey{typ: t, name: name})
+
+	return found
 }
@@ -3,7 +3,9 @@ package vessel
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -271,3 +273,52 @@ func TestProvideWithOpts_Transient(t *testing.T) {
 
 	assert.Equal(t, 2, counter)
 }
+
+func TestFromScope(t *testing.T) {
+	c := New()
+
+	type currentUser struct{ name string }
+
+	require.NoError(t, RegisterScopedWith[*testService](c, "profile",
+		FromScope[*currentUser]("user"),
+		FromScope[fmt.Stringer]("tenant"),
+		func(user *currentUser, tenant fmt.Stringer) (*testService, error) {
+			return &testService{value: user.name + "@" + tenant.String()}, nil
+		},
+	))
+
+	// Scope values are not dependencies
+	assert.Empty(t, c.Inspect("profile").Dependencies)
+	require.NoError(t, Validate(c))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	SetScoped(s, "user", &currentUser{name: "alice"})
+
+	_, err := ResolveScope[*testService](s, "profile")
+	assert.ErrorContains(t, err, "scope value tenant not set")
+
+	SetScoped(s, "tenant", "acme")
+
+	_, err = ResolveScope[*testService](s, "profile")
+	assert.ErrorContains(t, err, "type mismatch")
+
+	SetScoped[fmt.Stringer](s, "tenant", time.Second)
+
+	profile, err := ResolveScope[*testService](s, "profile")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@1s", profile.value)
+}
+
+func TestFromScope_RequiresScope(t *testing.T) {
+	c := New()
+
+	require.NoError(t, RegisterTransientWith[string](c, "greeting",
+		FromScope[string]("name"),
+		func(name string) (string, error) { return "hello " + name, nil },
+	))
+
+	_, err := c.Resolve("greeting")
+	assert.ErrorIs(t, err, ErrNoScope)
+}
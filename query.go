@@ -10,6 +10,11 @@ type ServiceQuery struct {
 	// Empty string matches all groups.
 	Group string
 
+	// Module filters by the module that registered the service, using its
+	// full path for nested modules (e.g. "billing.invoices").
+	// Empty string matches all services.
+	Module string
+
 	// Metadata filters by service metadata key-value pairs.
 	// All specified metadata must match for a service to be included.
 	Metadata map[string]string
@@ -57,6 +62,11 @@ func Query(c Vessel, query ServiceQuery) []ServiceInfo {
 			}
 		}
 
+		// Filter by module
+		if query.Module != "" && info.Metadata["__module"] != query.Module {
+			continue
+		}
+
 		// Filter by metadata
 		if len(query.Metadata) > 0 {
 			allMatch := true
@@ -105,6 +115,11 @@ func FindByGroup(c Vessel, group string) []ServiceInfo {
 	return Query(c, ServiceQuery{Group: group})
 }
 
+// FindByModule returns all services registered by a specific module.
+func FindByModule(c Vessel, module string) []ServiceInfo {
+	return Query(c, ServiceQuery{Module: module})
+}
+
 // FindByLifecycle returns all services with a specific lifecycle.
 func FindByLifecycle(c Vessel, lifecycle string) []ServiceInfo {
 	return Query(c, ServiceQuery{Lifecycle: lifecycle})
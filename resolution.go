@@ -0,0 +1,111 @@
+package vessel
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+)
+
+// construction is a name-based service being built. Constructions started
+// by a factory link to the one running it, forming the call chain.
+type construction struct {
+	name   string
+	owner  string // Service whose start runs the lifecycle hooks appended here
+	parent *construction
+	done   atomic.Bool
+}
+
+// hookOwner returns the service owning hooks appended while c is built,
+// or "" outside any construction.
+func (c *construction) hookOwner() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.owner
+}
+
+// cycle returns the path from the unfinished construction of name in the
+// chain ending at c back to name, e.g. [a b c a], or nil if there is none.
+// Finished constructions are skipped, since a factory may keep its Vessel,
+// say in a lazy dependency, and resolve through it later.
+func (c *construction) cycle(name string) []string {
+	var path []string
+
+	for current := c; current != nil; current = current.parent {
+		path = append(path, current.name)
+
+		if current.name == name && !current.done.Load() {
+			slices.Reverse(path)
+
+			return append(path, name)
+		}
+	}
+
+	return nil
+}
+
+// resolution is the Vessel a factory sees while the container constructs
+// its service. Resolving through it extends the call chain, so a factory
+// that resolves a service still under construction in the same chain
+// fails with the cycle instead of deadlocking or recursing forever.
+type resolution struct {
+	Vessel
+
+	container *containerImpl
+	current   *construction
+}
+
+// build runs create for name as part of the chain ending at from, giving
+// the factory a Vessel that continues the chain. Lifecycle hooks the
+// factory appends belong to owner.
+func (c *containerImpl) build(name, owner string, from *construction, create func(Vessel) (any, error)) (any, error) {
+	current := &construction{name: name, owner: owner, parent: from}
+	defer current.done.Store(true)
+
+	return create(&resolution{Vessel: c, container: c, current: current})
+}
+
+// Resolve returns a service by name, continuing the call chain.
+func (r *resolution) Resolve(name string) (any, error) {
+	return r.container.resolveVisible(context.Background(), name, r.current)
+}
+
+// ResolveContext returns a service by name using ctx, continuing the call chain.
+func (r *resolution) ResolveContext(ctx context.Context, name string) (any, error) {
+	return r.container.resolveVisible(ctx, name, r.current)
+}
+
+// ResolveReady resolves and starts a service by name. Starting a service
+// still under construction in the chain fails with the cycle.
+func (r *resolution) ResolveReady(ctx context.Context, name string) (any, error) {
+	if cycle := r.current.cycle(name); cycle != nil {
+		return nil, ErrCircularDependency(cycle)
+	}
+
+	return r.container.ResolveReady(ctx, name)
+}
+
+// typeResolution is the Vessel a type-based singleton's constructor sees.
+// Lifecycle hooks appended while building it, including by transients it
+// resolves, belong to the type.
+type typeResolution struct {
+	Vessel
+
+	owner typeKey
+}
+
+// containerOf returns the container behind the Vessel a factory received,
+// so helpers that need the container keep working inside factories.
+func containerOf(c Vessel) Vessel {
+	switch v := c.(type) {
+	case *resolution:
+		return v.container
+	case *scopeResolver:
+		return v.Vessel
+	case *typeResolution:
+		return containerOf(v.Vessel)
+	default:
+		return c
+	}
+}
@@ -0,0 +1,137 @@
+package vessel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolving returns a factory that resolves next before building a service.
+func resolving(name, next string) Factory {
+	return func(c Vessel) (any, error) {
+		if _, err := c.Resolve(next); err != nil {
+			return nil, err
+		}
+
+		return &mockService{name: name}, nil
+	}
+}
+
+// resolveWithin fails the test if resolving name doesn't return in time.
+func resolveWithin(t *testing.T, c Vessel, name string) error {
+	t.Helper()
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := c.Resolve(name)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("resolving %s did not return", name)
+
+		return nil
+	}
+}
+
+func TestResolve_RuntimeSingletonCycle(t *testing.T) {
+	c := New()
+
+	// Undeclared dependencies, so only resolution can see the cycle
+	require.NoError(t, c.Register("a", resolving("a", "b")))
+	require.NoError(t, c.Register("b", resolving("b", "c")))
+	require.NoError(t, c.Register("c", resolving("c", "a")))
+
+	err := resolveWithin(t, c, "a")
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[a b c a]")
+
+	// Nothing was cached, so resolving from elsewhere in the cycle reports it from there
+	err = resolveWithin(t, c, "b")
+	assert.Contains(t, err.Error(), "[b c a b]")
+}
+
+func TestResolve_RuntimeTransientCycle(t *testing.T) {
+	c := New()
+
+	require.NoError(t, c.Register("node", resolving("node", "node"), Transient()))
+
+	err := resolveWithin(t, c, "node")
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[node node]")
+}
+
+func TestResolve_RuntimeCycleThroughModule(t *testing.T) {
+	c := New()
+
+	require.NoError(t, Install(c, Module("app",
+		Service("a", resolving("a", "b")),
+		Service("b", resolving("b", "a")),
+		Export("a"),
+	)))
+
+	// Resolved through the exported alias, the cycle is still found
+	err := resolveWithin(t, c, "a")
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[app.a app.b app.a]")
+}
+
+func TestResolve_RuntimeCycleReady(t *testing.T) {
+	c := New()
+
+	require.NoError(t, c.Register("a", func(c Vessel) (any, error) {
+		return c.ResolveReady(context.Background(), "a")
+	}))
+
+	err := resolveWithin(t, c, "a")
+	assert.ErrorIs(t, err, ErrCircularDependencySentinel)
+}
+
+// lazyNode keeps a lazy reference to another instance of its own service.
+type lazyNode struct {
+	next *Lazy[*lazyNode]
+}
+
+func TestResolve_LazyAfterConstructionIsNotACycle(t *testing.T) {
+	c := New()
+
+	require.NoError(t, c.Register("node", func(c Vessel) (any, error) {
+		return &lazyNode{next: NewLazy[*lazyNode](c, "node")}, nil
+	}, Transient()))
+
+	node, err := Resolve[*lazyNode](c, "node")
+	require.NoError(t, err)
+
+	// The factory that captured c has returned, so this is a new resolution
+	next, err := node.next.Get()
+	require.NoError(t, err)
+	assert.NotSame(t, node, next)
+}
+
+func TestResolve_SharedDependencyIsNotACycle(t *testing.T) {
+	c := New()
+
+	require.NoError(t, c.Register("db", func(c Vessel) (any, error) {
+		return &mockService{name: "db"}, nil
+	}))
+	require.NoError(t, c.Register("repo", resolving("repo", "db")))
+	require.NoError(t, c.Register("api", func(c Vessel) (any, error) {
+		for _, name := range []string{"db", "repo", "db"} {
+			if _, err := c.Resolve(name); err != nil {
+				return nil, err
+			}
+		}
+
+		return &mockService{name: "api"}, nil
+	}))
+
+	_, err := c.Resolve("api")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,108 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Restart stops a service and everything that depends on it, disposes or
+// closes their cached instances as Close would, rebuilds them through their factories and starts
+// them again in dependency order. It is meant for recovering a broken
+// resource, such as a connection pool, without restarting the process.
+//
+// Example:
+//
+//	if errors.Is(err, driver.ErrBadConn) {
+//	    err = vessel.Restart(ctx, c, "db")
+//	}
+func Restart(ctx context.Context, c Vessel, name string) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Restart requires *containerImpl, got %T", c)
+	}
+
+	return impl.Restart(ctx, name)
+}
+
+// Restart stops, rebuilds and starts name and its dependents.
+//
+// Stop and release failures don't prevent the rebuild; they are returned
+// joined with any start failure. If a service fails to start again, the services
+// already restarted are stopped, as with Start. Only services registered
+// in this container are restarted; type-based singletons they resolved are
+// shared, so they and their lifecycle hooks are left running.
+func (c *containerImpl) Restart(ctx context.Context, name string) error {
+	c.mu.RLock()
+
+	if _, exists := c.services[name]; !exists {
+		c.mu.RUnlock()
+
+		return ErrServiceNotFound(name)
+	}
+
+	order, err := c.graph.TopologicalSort()
+	if err != nil {
+		c.mu.RUnlock()
+
+		return err
+	}
+
+	affected := c.graph.dependents(name, order)
+
+	c.mu.RUnlock()
+
+	var restartErrs []error
+
+	// Stop dependents before the services they depend on
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.stopService(ctx, affected[i]); err != nil {
+			restartErrs = append(restartErrs, NewServiceError(affected[i], "stop", err))
+		}
+	}
+
+	// Release the old instances in the same order they were stopped
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.release(ctx, c.discard(affected[i])); err != nil {
+			restartErrs = append(restartErrs, NewServiceError(affected[i], "close", err))
+		}
+	}
+
+	for _, svc := range affected {
+		if err := c.startService(ctx, svc); err != nil {
+			c.stopServices(ctx, affected)
+
+			restartErrs = append(restartErrs, NewServiceError(svc, "start", err))
+
+			break
+		}
+	}
+
+	if len(restartErrs) > 0 {
+		return NewServiceError(name, "restart", errors.Join(restartErrs...))
+	}
+
+	return nil
+}
+
+// discard drops the cached instance of a service and its lifecycle hooks
+// so the next resolution rebuilds it, and returns the dropped instance.
+func (c *containerImpl) discard(name string) any {
+	c.mu.RLock()
+	reg, exists := c.services[name]
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	reg.mu.Lock()
+	instance := reg.instance
+	reg.instance = nil
+	reg.started = false
+	reg.mu.Unlock()
+
+	c.lifecycle.forget(name)
+
+	return instance
+}
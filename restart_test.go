@@ -0,0 +1,141 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestart_CascadesToDependents(t *testing.T) {
+	c := New()
+	events := []string{}
+	builds := map[string]int{}
+
+	track := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			builds[name]++
+			events = append(events, "build "+name)
+
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart:     func() { events = append(events, "start "+name) },
+				onStop:      func() { events = append(events, "stop "+name) },
+			}, nil
+		}
+	}
+
+	require.NoError(t, c.Register("pool", track("pool")))
+	require.NoError(t, c.Register("repo", track("repo"), WithDependencies("pool")))
+	require.NoError(t, c.Register("api", track("api"), WithDependencies("repo")))
+	require.NoError(t, c.Register("metrics", track("metrics")))
+	require.NoError(t, c.Start(context.Background()))
+
+	oldPool := Must[*mockServiceWithCallback](c, "pool")
+	events = nil
+
+	require.NoError(t, Restart(context.Background(), c, "pool"))
+
+	assert.Equal(t, []string{
+		"stop api", "stop repo", "stop pool",
+		"build pool", "start pool",
+		"build repo", "start repo",
+		"build api", "start api",
+	}, events)
+
+	assert.NotSame(t, oldPool, Must[*mockServiceWithCallback](c, "pool"))
+	assert.Equal(t, 1, builds["metrics"], "unrelated services are untouched")
+	assert.True(t, c.IsStarted("api"))
+}
+
+func TestRestart_RerunsLifecycleHooks(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, c.Register("db", hookRecorder(&events, "db", nil)))
+	require.NoError(t, c.Start(context.Background()))
+
+	require.NoError(t, Restart(context.Background(), c, "db"))
+	assert.Equal(t, []string{"start db", "stop db", "start db"}, events)
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"start db", "stop db", "start db", "stop db"}, events)
+}
+
+func TestRestart_ReleasesOldInstances(t *testing.T) {
+	c := New()
+	events := []string{}
+	builds := 0
+
+	require.NoError(t, c.Register("pool", func(c Vessel) (any, error) {
+		builds++
+
+		return &recordingCloser{name: "pool", events: &events}, nil
+	}))
+	require.NoError(t, c.Register("repo", func(c Vessel) (any, error) {
+		return &recordingDisposable{recordingCloser{name: "repo", events: &events}}, nil
+	}, WithDependencies("pool")))
+	require.NoError(t, c.Start(context.Background()))
+
+	oldPool := Must[*recordingCloser](c, "pool")
+
+	require.NoError(t, Restart(context.Background(), c, "pool"))
+
+	// Dependents are released first, as with Close
+	assert.Equal(t, []string{"dispose repo", "close pool"}, events)
+	assert.Equal(t, 2, builds)
+	assert.NotSame(t, oldPool, Must[*recordingCloser](c, "pool"))
+}
+
+func TestRestart_LeavesSharedTypesRunning(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	provideRecordingDatabase(t, c, &events)
+	require.NoError(t, c.Register("repo", func(c Vessel) (any, error) {
+		return InjectType[*testDatabase](c)
+	}))
+	require.NoError(t, c.Start(context.Background()))
+
+	db := MustInjectType[*testDatabase](c)
+
+	require.NoError(t, Restart(context.Background(), c, "repo"))
+
+	// The rebuilt repo gets the same database, whose hooks keep running
+	assert.Equal(t, []string{"open"}, events)
+	assert.Same(t, db, Must[*testDatabase](c, "repo"))
+
+	require.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"open", "close"}, events)
+}
+
+func TestRestart_Errors(t *testing.T) {
+	c := New()
+
+	assert.ErrorIs(t, Restart(context.Background(), c, "missing"), ErrServiceNotFound("missing"))
+
+	stopErr := errors.New("stop failed")
+	startErr := errors.New("start failed")
+	attempts := 0
+
+	require.NoError(t, c.Register("pool", func(c Vessel) (any, error) {
+		attempts++
+
+		svc := &mockService{name: "pool", stopErr: stopErr}
+		if attempts > 1 {
+			svc.startErr = startErr
+		}
+
+		return svc, nil
+	}))
+	require.NoError(t, c.Start(context.Background()))
+
+	err := Restart(context.Background(), c, "pool")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, stopErr, "stop failures are reported")
+	assert.ErrorIs(t, err, startErr, "and don't prevent the rebuild")
+	assert.Equal(t, 2, attempts)
+	assert.False(t, c.IsStarted("pool"))
+}
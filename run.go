@@ -0,0 +1,109 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds Stop in Run when no WithShutdownTimeout is given.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// RunOption configures Run.
+type RunOption func(*runOptions)
+
+// runOptions holds Run configuration.
+type runOptions struct {
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+	exit            func(code int) // Called on a second signal
+}
+
+// WithShutdownTimeout bounds how long Run waits for Stop.
+func WithShutdownTimeout(timeout time.Duration) RunOption {
+	return func(o *runOptions) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// WithSignals replaces the signals that trigger shutdown, SIGINT and
+// SIGTERM by default.
+func WithSignals(signals ...os.Signal) RunOption {
+	return func(o *runOptions) {
+		o.signals = signals
+	}
+}
+
+// Run validates and starts the container, blocks until ctx is cancelled or
+// a shutdown signal arrives, then stops the container within the shutdown
+// timeout. A second signal during shutdown exits the process immediately
+// with status 1.
+//
+// The returned error joins every Stop failure and reports a shutdown that
+// ran out of time. Cancelling ctx is a normal shutdown, not an error.
+//
+// Example:
+//
+//	func main() {
+//	    c := app.NewContainer()
+//	    if err := vessel.Run(context.Background(), c, vessel.WithShutdownTimeout(10*time.Second)); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+func Run(ctx context.Context, c Vessel, opts ...RunOption) error {
+	options := runOptions{
+		shutdownTimeout: DefaultShutdownTimeout,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+		exit:            os.Exit,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if impl, ok := containerOf(c).(*containerImpl); ok {
+		if err := impl.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Listen before starting so an early signal isn't lost
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, options.signals...)
+	defer signal.Stop(signals)
+
+	if err := c.Start(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-signals:
+	}
+
+	// Keep ctx values but not its cancellation for the shutdown
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), options.shutdownTimeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+		select {
+		case <-signals:
+			options.exit(1)
+		case <-stopped:
+		}
+	}()
+
+	stopErr := c.Stop(stopCtx)
+
+	if errors.Is(stopCtx.Err(), context.DeadlineExceeded) {
+		return errors.Join(stopErr, fmt.Errorf("shutdown timed out after %s", options.shutdownTimeout))
+	}
+
+	return stopErr
+}
@@ -0,0 +1,144 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runInBackground calls Run and returns its result channel.
+func runInBackground(ctx context.Context, c Vessel, opts ...RunOption) <-chan error {
+	result := make(chan error, 1)
+
+	go func() {
+		result <- Run(ctx, c, opts...)
+	}()
+
+	return result
+}
+
+func TestRun_StopsOnContextCancel(t *testing.T) {
+	c := New()
+	svc := &mockService{name: "svc", healthy: true}
+	require.NoError(t, RegisterValue(c, "svc", svc))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := runInBackground(ctx, c)
+
+	require.Eventually(t, func() bool { return c.IsStarted("svc") }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return")
+	}
+
+	assert.True(t, svc.stopped)
+}
+
+func TestRun_StopsOnSignal(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterValue(c, "svc", &mockService{name: "svc", healthy: true}))
+
+	result := runInBackground(context.Background(), c, WithSignals(os.Interrupt))
+
+	require.Eventually(t, func() bool { return c.IsStarted("svc") }, time.Second, time.Millisecond)
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(os.Interrupt))
+
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+func TestRun_SecondSignalForcesExit(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	stopping := make(chan struct{})
+
+	require.NoError(t, RegisterValue(c, "svc", &mockServiceWithCallback{
+		mockService: mockService{name: "svc", healthy: true},
+		onStop: func() {
+			close(stopping)
+			<-release
+		},
+	}))
+
+	exited := make(chan int, 1)
+	result := runInBackground(context.Background(), c,
+		WithSignals(os.Interrupt),
+		func(o *runOptions) { o.exit = func(code int) { exited <- code } },
+	)
+
+	require.Eventually(t, func() bool { return c.IsStarted("svc") }, time.Second, time.Millisecond)
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(os.Interrupt))
+
+	<-stopping
+	require.NoError(t, process.Signal(os.Interrupt))
+
+	select {
+	case code := <-exited:
+		assert.Equal(t, 1, code)
+	case <-time.After(time.Second):
+		t.Fatal("second signal did not force exit")
+	}
+
+	close(release)
+	<-result
+}
+
+func TestRun_ShutdownTimeout(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	defer close(release)
+
+	require.NoError(t, RegisterValue(c, "svc", &mockServiceWithCallback{
+		mockService: mockService{name: "svc", healthy: true},
+		onStop:      func() { <-release },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := runInBackground(ctx, c, WithShutdownTimeout(20*time.Millisecond))
+
+	require.Eventually(t, func() bool { return c.IsStarted("svc") }, time.Second, time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-result:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "shutdown timed out")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+func TestRun_ValidatesAndReportsStartErrors(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Register("api", func(c Vessel) (any, error) { return nil, nil }, WithDependencies("missing")))
+
+	var report *ValidationError
+	assert.True(t, errors.As(Run(context.Background(), c), &report))
+
+	c = New()
+	startErr := errors.New("bind failed")
+	require.NoError(t, RegisterValue(c, "svc", &mockService{name: "svc", startErr: startErr}))
+
+	assert.ErrorIs(t, Run(context.Background(), c), startErr)
+}
@@ -0,0 +1,135 @@
+package vessel
+
+import (
+	"context"
+	"log"
+	"net/http"
+)
+
+// ScopeKeyRequest is the scope context key ScopeMiddleware stores the
+// current *http.Request under.
+const ScopeKeyRequest = "http.request"
+
+// scopeContextKey is the context key for the current scope.
+type scopeContextKey struct{}
+
+// WithScope returns a copy of ctx carrying s.
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, s)
+}
+
+// ScopeFromContext returns the scope carried by ctx, if any.
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(scopeContextKey{}).(Scope)
+
+	return s, ok && s != nil
+}
+
+// ResolveFromContext resolves a service with type safety from the scope
+// carried by ctx, passing ctx through the resolution. It returns ErrNoScope
+// when ctx carries no scope.
+//
+// Example:
+//
+//	func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//	    session, err := vessel.ResolveFromContext[*Session](r.Context(), "session")
+//	    ...
+//	}
+func ResolveFromContext[T any](ctx context.Context, name string) (T, error) {
+	s, ok := ScopeFromContext(ctx)
+	if !ok {
+		var zero T
+
+		return zero, ErrNoScope
+	}
+
+	return ResolveScopeCtx[T](ctx, s, name)
+}
+
+// ScopeMiddlewareOption configures ScopeMiddleware.
+type ScopeMiddlewareOption func(*scopeMiddlewareOptions)
+
+// scopeMiddlewareOptions holds ScopeMiddleware configuration.
+type scopeMiddlewareOptions struct {
+	seeds      []func(r *http.Request, s Scope)
+	onEndError func(r *http.Request, err error)
+}
+
+// WithScopeSeed adds a function that stores request values in each new
+// scope, typically with SetScoped, before the handler runs.
+func WithScopeSeed(seed func(r *http.Request, s Scope)) ScopeMiddlewareOption {
+	return func(o *scopeMiddlewareOptions) {
+		o.seeds = append(o.seeds, seed)
+	}
+}
+
+// WithScopeEndError sets the function called when ending a request's scope
+// fails. By default the error is logged.
+func WithScopeEndError(onEndError func(r *http.Request, err error)) ScopeMiddlewareOption {
+	return func(o *scopeMiddlewareOptions) {
+		o.onEndError = onEndError
+	}
+}
+
+// ScopeMiddleware returns HTTP middleware that runs each request in its own
+// scope. The scope is stored in the request context (see ScopeFromContext
+// and ResolveFromContext) and seeded with the request under
+// ScopeKeyRequest, then with any WithScopeSeed values. It is always ended
+// once the handler returns, even if the handler panics.
+//
+// When the request context already carries a scope, such as one from an
+// outer ScopeMiddleware, the request scope is nested in it.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/orders", ordersHandler)
+//
+//	handler := vessel.ScopeMiddleware(c, vessel.WithScopeSeed(func(r *http.Request, s vessel.Scope) {
+//	    vessel.SetScoped(s, "requestID", r.Header.Get("X-Request-ID"))
+//	}))(mux)
+func ScopeMiddleware(c Vessel, opts ...ScopeMiddlewareOption) func(http.Handler) http.Handler {
+	options := scopeMiddlewareOptions{
+		onEndError: func(r *http.Request, err error) {
+			log.Printf("vessel: ending scope for %s %s: %v", r.Method, r.URL.Path, err)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := beginRequestScope(r.Context(), c)
+
+			defer func() {
+				if err := s.End(); err != nil {
+					options.onEndError(r, err)
+				}
+			}()
+
+			r = r.WithContext(WithScope(r.Context(), s))
+
+			SetScoped(s, ScopeKeyRequest, r)
+
+			for _, seed := range options.seeds {
+				seed(r, s)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// beginRequestScope begins a scope nested in the one carried by ctx, or a
+// new scope on c when there is none.
+func beginRequestScope(ctx context.Context, c Vessel) Scope {
+	if outer, ok := ScopeFromContext(ctx); ok {
+		if s, err := BeginNestedScope(outer); err == nil {
+			return s
+		}
+	}
+
+	return c.BeginScope()
+}
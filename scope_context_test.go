@@ -0,0 +1,128 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeFromContext(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterScoped(c, "session", func(c Vessel) (*testService, error) {
+		return &testService{value: "session"}, nil
+	}))
+
+	_, ok := ScopeFromContext(context.Background())
+	assert.False(t, ok)
+
+	_, err := ResolveFromContext[*testService](context.Background(), "session")
+	assert.ErrorIs(t, err, ErrNoScope)
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	ctx := WithScope(context.Background(), s)
+
+	got, ok := ScopeFromContext(ctx)
+	require.True(t, ok)
+	assert.Same(t, s, got)
+
+	session, err := ResolveFromContext[*testService](ctx, "session")
+	require.NoError(t, err)
+	assert.Equal(t, "session", session.value)
+
+	_, err = ResolveFromContext[*mockService](ctx, "session")
+	assert.Error(t, err)
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, c.Register("tx", func(c Vessel) (any, error) {
+		return &recordingCloser{name: "tx", events: &events}, nil
+	}, Scoped()))
+
+	var seen *http.Request
+
+	handler := ScopeMiddleware(c, WithScopeSeed(func(r *http.Request, s Scope) {
+		SetScoped(s, "requestID", r.Header.Get("X-Request-ID"))
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := ScopeFromContext(r.Context())
+		require.True(t, ok)
+
+		seen, _ = GetScoped[*http.Request](s, ScopeKeyRequest)
+		requestID, _ := GetScoped[string](s, "requestID")
+		_, _ = w.Write([]byte(requestID))
+
+		_, err := ResolveFromContext[*recordingCloser](r.Context(), "tx")
+		require.NoError(t, err)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "abc-123", rec.Body.String())
+	require.NotNil(t, seen)
+	assert.Equal(t, "/orders", seen.URL.Path)
+	assert.Equal(t, []string{"close tx"}, events, "the scope ends after the request")
+}
+
+func TestScopeMiddleware_EndsOnPanic(t *testing.T) {
+	c := New()
+	events := []string{}
+	endErr := errors.New("close failed")
+
+	require.NoError(t, c.Register("tx", func(c Vessel) (any, error) {
+		return &recordingCloser{name: "tx", events: &events, err: endErr}, nil
+	}, Scoped()))
+
+	var reported error
+
+	handler := ScopeMiddleware(c, WithScopeEndError(func(r *http.Request, err error) {
+		reported = err
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ResolveFromContext[*recordingCloser](r.Context(), "tx")
+
+		panic("boom")
+	}))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.Equal(t, []string{"close tx"}, events)
+	assert.ErrorIs(t, reported, endErr)
+}
+
+func TestScopeMiddleware_NestsInOuterScope(t *testing.T) {
+	c := New()
+	require.NoError(t, RegisterScoped(c, "tenant", func(c Vessel) (*testService, error) {
+		return &testService{value: "tenant"}, nil
+	}))
+
+	outer := c.BeginScope()
+	defer func() { _ = outer.End() }()
+
+	tenant, err := ResolveScope[*testService](outer, "tenant")
+	require.NoError(t, err)
+
+	handler := ScopeMiddleware(c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, _ := ScopeFromContext(r.Context())
+		assert.NotSame(t, outer, s)
+
+		inherited, err := ResolveFromContext[*testService](r.Context(), "tenant")
+		require.NoError(t, err)
+		assert.Same(t, tenant, inherited)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(WithScope(req.Context(), outer)))
+}
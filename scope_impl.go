@@ -1,7 +1,10 @@
 package vessel
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/xraph/go-utils/di"
@@ -10,63 +13,267 @@ import (
 // scope implements Scope.
 type scope struct {
 	parent    *containerImpl
+	outer     *scope   // Enclosing scope of a nested scope, nil at the top
+	children  []*scope // Open nested scopes, in creation order
 	instances map[string]any
-	context   map[string]any // Context storage for request-specific data
+	provided  map[string]any         // Scope-local instances added with Provide
+	pending   map[string]*scopedCall // Scoped instances under construction
+	created   []scopedInstance       // Scoped instances in creation order
+	context   map[string]any         // Context storage for request-specific data
+	tracked   *trackedScope          // The container's record of this scope
 	mu        sync.RWMutex
+	ending    bool // End is in progress
 	ended     bool
 }
 
+// scopedInstance records a scoped instance and what it depends on, so End
+// can stop and dispose dependents before their dependencies.
+type scopedInstance struct {
+	name     string
+	instance any
+	deps     []string
+	owner    *containerImpl // Container the service is registered in
+}
+
+// scopedCall is the construction of one scoped or transient instance.
+// Resolutions of the same scoped service wait for it instead of building
+// their own.
+type scopedCall struct {
+	name       string
+	done       chan struct{}
+	instance   any
+	err        error
+	caller     *scopedCall // Construction whose factory started this one
+	waitingFor *scopedCall // Construction this one is blocked on, guarded by scope.mu
+}
+
+// cycle returns the path from the unfinished construction of name among
+// call and its callers back to name, e.g. [a b a], or nil if there is none.
+// Like construction.cycle, it skips finished constructions.
+func (call *scopedCall) cycle(name string) []string {
+	var path []string
+
+	for current := call; current != nil; current = current.caller {
+		path = append(path, current.name)
+
+		if current.name == name && !current.finished() {
+			slices.Reverse(path)
+
+			return append(path, name)
+		}
+	}
+
+	return nil
+}
+
+// finished reports whether the construction has returned.
+func (call *scopedCall) finished() bool {
+	select {
+	case <-call.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// cycleTo returns the cycle formed if call waited for target, or nil. It
+// follows what target is waiting for, which may be constructions running
+// in other goroutines. The caller must hold scope.mu.
+func (call *scopedCall) cycleTo(target *scopedCall) []string {
+	var path []string
+
+	for current := target; current != nil; current = current.waitingFor {
+		path = append(path, current.name)
+
+		if current == call {
+			return append(path, target.name)
+		}
+	}
+
+	return nil
+}
+
 // newScope creates a new scope.
 func newScope(parent *containerImpl) *scope {
 	return &scope{
 		parent:    parent,
 		instances: make(map[string]any),
+		pending:   make(map[string]*scopedCall),
 		context:   make(map[string]any),
 	}
 }
 
-// Resolve returns a service by name from this scope.
-func (s *scope) Resolve(name string) (any, error) {
+// BeginScope creates a scope nested in this one. The nested scope reuses the
+// scoped instances of the scopes enclosing it, creating its own only for
+// services none of them has resolved yet, and inherits their context
+// values. It is ended when this scope ends, if it wasn't already.
+//
+// BeginScope on an ended scope returns a scope that is already ended.
+func (s *scope) BeginScope() Scope {
+	child := newScope(s.parent)
+	child.outer = s
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.ended {
+	if s.ended || s.ending {
+		child.ended = true
+
+		return child
+	}
+
+	s.children = append(s.children, child)
+	s.parent.scopes.track(child)
+
+	return child
+}
+
+// BeginNestedScope creates a scope nested in parent. See (*scope).BeginScope.
+//
+// Example:
+//
+//	job := c.BeginScope()
+//	defer job.End()
+//
+//	for _, item := range items {
+//	    itemScope, _ := vessel.BeginNestedScope(job)
+//	    process(itemScope, item)
+//	    itemScope.End()
+//	}
+func BeginNestedScope(parent Scope) (Scope, error) {
+	s, ok := parent.(*scope)
+	if !ok {
+		return nil, fmt.Errorf("BeginNestedScope requires *scope, got %T", parent)
+	}
+
+	return s.BeginScope(), nil
+}
+
+// Resolve returns a service by name from this scope.
+func (s *scope) Resolve(name string) (any, error) {
+	return s.ResolveContext(context.Background(), name)
+}
+
+// ResolveContext returns a service by name from this scope using ctx for
+// factories and for singletons resolved from the parent container.
+//
+// Factories of scoped and transient services receive a resolver bound to
+// this scope (see ScopeOf), so they can depend on other scoped services.
+// Each scoped service is constructed once per scope: concurrent
+// resolutions of it wait for the first, while different services are
+// constructed in parallel. A factory that ends up waiting for itself, in
+// any goroutine, fails with a circular dependency error.
+func (s *scope) ResolveContext(ctx context.Context, name string) (any, error) {
+	return s.resolve(ctx, name, nil)
+}
+
+// resolve resolves name on behalf of caller, the construction whose
+// factory asked for it, or nil when resolving from outside a factory.
+func (s *scope) resolve(ctx context.Context, name string, caller *scopedCall) (any, error) {
+	s.mu.RLock()
+	ended := s.ended
+	instance, cached := s.instances[name]
+	s.mu.RUnlock()
+
+	if ended {
 		return nil, ErrScopeEnded
 	}
 
-	// Get registration from parent
-	s.parent.mu.RLock()
-	reg, exists := s.parent.services[name]
-	s.parent.mu.RUnlock()
+	if s.parent.hidden(ctx, name) {
+		return nil, ErrServiceNotFound(name)
+	}
+
+	if cached {
+		return instance, nil
+	}
+
+	if instance, ok := s.local(name); ok {
+		return instance, nil
+	}
+
+	// Get registration from parent (or the container it inherits from)
+	reg, owner, exists := s.parent.lookup(name)
+	if exists && owner != s.parent && owner.hidden(ctx, name) {
+		return nil, ErrServiceNotFound(name)
+	}
 
 	if !exists {
+		if s.parent.Has(name) {
+			// Registered in a parent that isn't a vessel container
+			return resolveContext(ctx, s.parent, name)
+		}
+
 		return nil, ErrServiceNotFound(name)
 	}
 
-	// Singleton services: resolve from parent
+	// Singleton services: resolve from the owning container
 	if reg.singleton {
-		return s.parent.Resolve(name)
+		return owner.ResolveContext(ctx, name)
+	}
+
+	// The module that asked doesn't pass on to the factories
+	ctx = outsideModule(ctx)
+
+	// Transient services: always create new, as part of the caller's chain
+	if !reg.scoped {
+		return s.transient(ctx, name, caller, reg, owner)
 	}
 
 	// Scoped services: cache in this scope
-	if reg.scoped {
-		if instance, ok := s.instances[name]; ok {
-			return instance, nil
-		}
+	if instance, ok := s.inherited(name); ok {
+		return instance, nil
+	}
 
-		// Create new instance for this scope
-		instance, err := reg.factory(s.parent)
-		if err != nil {
-			return nil, NewServiceError(name, "resolve", err)
-		}
+	s.mu.Lock()
+
+	if s.ended {
+		s.mu.Unlock()
 
-		s.instances[name] = instance
+		return nil, ErrScopeEnded
+	}
+
+	if instance, ok := s.instances[name]; ok {
+		s.mu.Unlock()
 
 		return instance, nil
 	}
 
-	// Transient services: always create new
-	instance, err := reg.factory(s.parent)
+	if call, ok := s.pending[name]; ok {
+		return s.wait(ctx, call, caller)
+	}
+
+	call := &scopedCall{name: name, done: make(chan struct{}), caller: caller}
+	s.pending[name] = call
+
+	if caller != nil {
+		caller.waitingFor = call
+	}
+
+	s.mu.Unlock()
+
+	return s.construct(ctx, call, caller, reg, owner)
+}
+
+// transient creates a transient instance for caller. A transient that is
+// still being built further up the same chain would recurse forever, so it
+// fails with the cycle instead.
+func (s *scope) transient(ctx context.Context, name string, caller *scopedCall, reg *serviceRegistration, owner *containerImpl) (any, error) {
+	if cycle := caller.cycle(name); cycle != nil {
+		return nil, ErrCircularDependency(cycle)
+	}
+
+	call := &scopedCall{name: name, done: make(chan struct{}), caller: caller}
+	defer close(call.done)
+
+	if caller != nil {
+		s.mu.Lock()
+		caller.waitingFor = call
+		s.mu.Unlock()
+
+		defer s.doneWaiting(caller)
+	}
+
+	instance, err := reg.create(ctx, s.resolver(owner, call))
 	if err != nil {
 		return nil, NewServiceError(name, "resolve", err)
 	}
@@ -74,39 +281,346 @@ func (s *scope) Resolve(name string) (any, error) {
 	return instance, nil
 }
 
-// End cleans up all scoped services in this scope.
-func (s *scope) End() error {
+// wait waits for another resolution to construct call. It is entered with
+// s.mu held and releases it.
+func (s *scope) wait(ctx context.Context, call, caller *scopedCall) (any, error) {
+	if caller != nil {
+		if cycle := caller.cycleTo(call); cycle != nil {
+			s.mu.Unlock()
+
+			return nil, ErrCircularDependency(cycle)
+		}
+
+		caller.waitingFor = call
+	}
+
+	s.mu.Unlock()
+
+	defer s.doneWaiting(caller)
+
+	select {
+	case <-call.done:
+		return call.instance, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// doneWaiting clears what caller is blocked on.
+func (s *scope) doneWaiting(caller *scopedCall) {
+	if caller == nil {
+		return
+	}
+
+	s.mu.Lock()
+	caller.waitingFor = nil
+	s.mu.Unlock()
+}
+
+// construct creates and starts the scoped instance for call without
+// holding the scope's lock, so its factory can resolve through the scope,
+// then caches it and wakes any resolution waiting for it.
+func (s *scope) construct(ctx context.Context, call, caller *scopedCall, reg *serviceRegistration, owner *containerImpl) (any, error) {
+	defer s.doneWaiting(caller)
+
+	finished := false
+
+	// Release waiters even if the factory panics
+	defer func() {
+		if !finished {
+			s.finish(call, nil, fmt.Errorf("scoped service %s: construction panicked", call.name))
+		}
+	}()
+
+	fail := func(err error) (any, error) {
+		finished = true
+		s.finish(call, nil, err)
+
+		return nil, err
+	}
+
+	instance, err := reg.create(ctx, s.resolver(owner, call))
+	if err != nil {
+		return fail(NewServiceError(call.name, "resolve", err))
+	}
+
+	// Auto-start like singletons. A failed instance is not cached, so End
+	// won't see it; release it here
+	if err := owner.autoStart(ctx, call.name, instance); err != nil {
+		if releaseErr := owner.release(ctx, instance); releaseErr != nil {
+			err = errors.Join(err, NewServiceError(call.name, "close", releaseErr))
+		}
+
+		return fail(err)
+	}
+
+	created := scopedInstance{
+		name:     call.name,
+		instance: instance,
+		deps:     reg.dependencies,
+		owner:    owner,
+	}
+
+	finished = true
+
+	if !s.finish(call, &created, nil) {
+		// The scope ended meanwhile, so nothing else will release it
+		_ = created.stop(ctx)
+		if dispose := disposer(instance); dispose != nil {
+			_ = dispose()
+		}
+
+		return nil, ErrScopeEnded
+	}
+
+	return instance, nil
+}
+
+// finish records the outcome of call and wakes its waiters. It reports
+// false if created could not be cached because the scope has ended.
+func (s *scope) finish(call *scopedCall, created *scopedInstance, err error) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.ended {
-		return ErrScopeEnded
+	delete(s.pending, call.name)
+
+	cached := false
+
+	switch {
+	case created == nil:
+		call.err = err
+	case s.ended:
+		call.err = ErrScopeEnded
+	default:
+		s.instances[call.name] = created.instance
+		s.created = append(s.created, *created)
+		s.parent.scopes.resolved(s.tracked, call.name)
+		call.instance = created.instance
+		cached = true
 	}
 
-	// Dispose of scoped instances in reverse order
+	close(call.done)
+
+	return cached
+}
+
+// End stops the scoped instances created in this scope that implement
+// di.Service, then disposes them through di.Disposable.Dispose or
+// io.Closer.Close. Both passes run in reverse creation order, and never
+// before a scoped instance that declares a dependency on them. Each Stop
+// gets the stop deadline of the container the service is registered in.
+// Every instance is stopped and disposed even if others fail; the returned
+// error joins the failures.
+func (s *scope) End() error {
 	var errs []error
 
-	for name, instance := range s.instances {
-		if disposable, ok := instance.(di.Disposable); ok {
-			if err := disposable.Dispose(); err != nil {
-				errs = append(errs, fmt.Errorf("failed to dispose %s: %w", name, err))
-			}
+	// Nested scopes end first. They lock this scope to look up inherited
+	// instances and to detach, so it must not be held here.
+	s.mu.Lock()
+	if s.ended || s.ending {
+		s.mu.Unlock()
+
+		return ErrScopeEnded
+	}
+
+	s.ending = true
+	children := s.children
+	s.children = nil
+	s.mu.Unlock()
+
+	for i := len(children) - 1; i >= 0; i-- {
+		if err := children[i].End(); err != nil && !errors.Is(err, ErrScopeEnded) {
+			errs = append(errs, err)
+		}
+	}
+
+	defer s.detach()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := disposalOrder(s.created)
+
+	for _, inst := range order {
+		if err := inst.stop(context.Background()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", inst.name, err))
+		}
+	}
+
+	for _, inst := range order {
+		dispose := disposer(inst.instance)
+		if dispose == nil {
+			continue
+		}
+
+		if err := dispose(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to dispose %s: %w", inst.name, err))
 		}
 	}
 
 	s.instances = nil
+	s.provided = nil
+	s.created = nil
 	s.context = nil
+	s.ending = false
 	s.ended = true
 
+	s.parent.scopes.untrack(s.tracked)
+
 	if len(errs) > 0 {
-		return fmt.Errorf("scope cleanup errors: %v", errs)
+		return fmt.Errorf("scope cleanup errors: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// inherited returns the scoped instance named name from the nearest
+// enclosing scope that has one.
+func (s *scope) inherited(name string) (any, bool) {
+	for outer := s.outer; outer != nil; outer = outer.outer {
+		outer.mu.RLock()
+		instance, ok := outer.instances[name]
+		outer.mu.RUnlock()
+
+		if ok {
+			return instance, true
+		}
+	}
+
+	return nil, false
+}
+
+// detach removes an ended scope from its enclosing scope's children.
+func (s *scope) detach() {
+	if s.outer == nil {
+		return
+	}
+
+	s.outer.mu.Lock()
+	defer s.outer.mu.Unlock()
+
+	for i, child := range s.outer.children {
+		if child == s {
+			s.outer.children = append(s.outer.children[:i], s.outer.children[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// stop stops the instance if it implements di.Service.
+func (inst scopedInstance) stop(ctx context.Context) error {
+	svc, ok := inst.instance.(di.Service)
+	if !ok {
+		return nil
+	}
+
+	stopCtx, cancel := inst.owner.stopContext(ctx)
+	defer cancel()
+
+	return stopWithDeadline(stopCtx, svc.Stop)
+}
+
+// disposalOrder returns created in reverse creation order, moving each
+// instance after every instance that depends on it.
+func disposalOrder(created []scopedInstance) []scopedInstance {
+	dependents := make(map[string][]int, len(created))
+	for i, inst := range created {
+		for _, dep := range inst.deps {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	order := make([]scopedInstance, 0, len(created))
+	visited := make([]bool, len(created))
+
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+
+		visited[i] = true
+
+		// Latest dependents first, matching the overall reverse order
+		deps := dependents[created[i].name]
+		for j := len(deps) - 1; j >= 0; j-- {
+			visit(deps[j])
+		}
+
+		order = append(order, created[i])
 	}
 
+	for i := len(created) - 1; i >= 0; i-- {
+		visit(i)
+	}
+
+	return order
+}
+
+// Provide adds a scope-local instance that resolves like a service
+// registered under name, shadowing any container registration within this
+// scope and the scopes nested in it. The scope doesn't own the value: End
+// neither stops nor disposes it.
+func (s *scope) Provide(name string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return ErrScopeEnded
+	}
+
+	if _, exists := s.provided[name]; exists {
+		return ErrServiceAlreadyExists(name)
+	}
+
+	if s.provided == nil {
+		s.provided = make(map[string]any)
+	}
+
+	s.provided[name] = value
+
 	return nil
 }
 
-// Has checks if a service is registered (delegates to parent container).
+// ProvideInScope adds a scope-local instance to s. See (*scope).Provide.
+//
+// Example:
+//
+//	vessel.ProvideInScope(scope, "currentUser", user)
+//	profile, err := vessel.ResolveScope[*Profile](scope, "profile") // may inject "currentUser"
+func ProvideInScope(s Scope, name string, value any) error {
+	impl, ok := s.(*scope)
+	if !ok {
+		return fmt.Errorf("ProvideInScope requires *scope, got %T", s)
+	}
+
+	return impl.Provide(name, value)
+}
+
+// local returns the instance added with Provide under name in this scope
+// or the nearest enclosing scope.
+func (s *scope) local(name string) (any, bool) {
+	for current := s; current != nil; current = current.outer {
+		current.mu.RLock()
+		value, ok := current.provided[name]
+		current.mu.RUnlock()
+
+		if ok {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Has checks if a service is provided in this scope or registered in the
+// parent container.
 func (s *scope) Has(name string) bool {
+	if _, ok := s.local(name); ok {
+		return true
+	}
+
 	return s.parent.Has(name)
 }
 
@@ -146,11 +660,24 @@ func (s *scope) Set(key string, value any) {
 	s.context[key] = value
 }
 
-// Get retrieves a value from the scope context.
+// Get retrieves a value from the scope context, falling back to the
+// scopes enclosing a nested scope.
 func (s *scope) Get(key string) (any, bool) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	for current := s; current != nil; current = current.outer {
+		current.mu.RLock()
+		value, ok := current.context[key]
+		ended := current.ended
+		current.mu.RUnlock()
+
+		// An ended scope no longer sees any context
+		if ended {
+			return nil, false
+		}
+
+		if ok {
+			return value, true
+		}
+	}
 
-	value, ok := s.context[key]
-	return value, ok
+	return nil, false
 }
@@ -0,0 +1,60 @@
+package vessel
+
+import "context"
+
+// scopeResolver is the Vessel a factory sees when a scope creates its
+// service. Resolutions go through the scope, so scoped services are shared
+// with the rest of the scope; everything else is passed to the container
+// the service is registered in.
+type scopeResolver struct {
+	Vessel
+
+	scope *scope
+	call  *scopedCall // Construction the factory belongs to, if any
+}
+
+// resolver returns the Vessel given to factories of services registered in
+// owner and created by this scope as part of call.
+func (s *scope) resolver(owner *containerImpl, call *scopedCall) *scopeResolver {
+	return &scopeResolver{Vessel: owner, scope: s, call: call}
+}
+
+// Resolve returns a service by name from the scope.
+func (r *scopeResolver) Resolve(name string) (any, error) {
+	return r.scope.resolve(context.Background(), name, r.call)
+}
+
+// ResolveContext returns a service by name from the scope using ctx.
+func (r *scopeResolver) ResolveContext(ctx context.Context, name string) (any, error) {
+	return r.scope.resolve(ctx, name, r.call)
+}
+
+// Has checks if a service is provided in the scope or registered.
+func (r *scopeResolver) Has(name string) bool {
+	return r.scope.Has(name)
+}
+
+// BeginScope creates a scope nested in the scope.
+func (r *scopeResolver) BeginScope() Scope {
+	return r.scope.BeginScope()
+}
+
+// ScopeOf returns the scope a factory is running in when c is the Vessel
+// the factory received, for example to read values stored with SetScoped.
+// It reports false for factories called outside a scope.
+//
+// Example:
+//
+//	vessel.RegisterScoped(c, "audit", func(c vessel.Vessel) (*Audit, error) {
+//	    s, _ := vessel.ScopeOf(c)
+//	    requestID, _ := vessel.GetScoped[string](s, "requestID")
+//	    return NewAudit(requestID), nil
+//	})
+func ScopeOf(c Vessel) (Scope, bool) {
+	r, ok := c.(*scopeResolver)
+	if !ok {
+		return nil, false
+	}
+
+	return r.scope, true
+}
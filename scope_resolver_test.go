@@ -0,0 +1,295 @@
+package vessel
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scopedTx and unitOfWork model a scoped service depending on another.
+type scopedTx struct {
+	recordingCloser
+}
+
+type unitOfWork struct {
+	recordingCloser
+
+	tx *scopedTx
+}
+
+func TestScope_FactoriesResolveThroughScope(t *testing.T) {
+	c := New()
+	events := []string{}
+	txBuilds := 0
+
+	require.NoError(t, RegisterScoped(c, "tx", func(c Vessel) (*scopedTx, error) {
+		txBuilds++
+
+		return &scopedTx{recordingCloser{name: "tx", events: &events}}, nil
+	}))
+	require.NoError(t, RegisterScopedWith[*unitOfWork](c, "uow",
+		Inject[*scopedTx]("tx"),
+		func(tx *scopedTx) (*unitOfWork, error) {
+			return &unitOfWork{recordingCloser{name: "uow", events: &events}, tx}, nil
+		},
+	))
+
+	s := c.BeginScope()
+
+	uow, err := ResolveScope[*unitOfWork](s, "uow")
+	require.NoError(t, err)
+
+	tx, err := ResolveScope[*scopedTx](s, "tx")
+	require.NoError(t, err)
+	assert.Same(t, tx, uow.tx, "the dependency is the scope's instance")
+	assert.Equal(t, 1, txBuilds)
+
+	other := c.BeginScope()
+	otherUow, err := ResolveScope[*unitOfWork](other, "uow")
+	require.NoError(t, err)
+	assert.NotSame(t, tx, otherUow.tx)
+	require.NoError(t, other.End())
+
+	events = nil
+
+	require.NoError(t, s.End())
+	assert.Equal(t, []string{"close uow", "close tx"}, events)
+}
+
+func TestScope_TransientSeesScope(t *testing.T) {
+	c := New()
+
+	require.NoError(t, RegisterScoped(c, "session", func(c Vessel) (*testService, error) {
+		return &testService{value: "session"}, nil
+	}))
+	require.NoError(t, RegisterTransient(c, "handler", func(c Vessel) (*testService, error) {
+		session, err := Resolve[*testService](c, "session")
+		if err != nil {
+			return nil, err
+		}
+
+		return &testService{value: "handler:" + session.value}, nil
+	}))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	handler, err := ResolveScope[*testService](s, "handler")
+	require.NoError(t, err)
+	assert.Equal(t, "handler:session", handler.value)
+
+	// Outside a scope the scoped dependency is still rejected
+	_, err = c.Resolve("handler")
+	assert.Error(t, err)
+}
+
+func TestScopeOf(t *testing.T) {
+	c := New()
+
+	require.NoError(t, ProvideConstructor(c, func() *testDatabase { return &testDatabase{} }))
+	require.NoError(t, RegisterScoped(c, "audit", func(c Vessel) (*testService, error) {
+		s, ok := ScopeOf(c)
+		if !ok {
+			return nil, assert.AnError
+		}
+
+		// Type-based helpers keep working inside scoped factories
+		if _, err := InjectType[*testDatabase](c); err != nil {
+			return nil, err
+		}
+
+		requestID, _ := GetScoped[string](s, "requestID")
+
+		return &testService{value: requestID}, nil
+	}))
+
+	_, ok := ScopeOf(c)
+	assert.False(t, ok)
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	SetScoped(s, "requestID", "abc-123")
+
+	audit, err := ResolveScope[*testService](s, "audit")
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", audit.value)
+}
+
+func TestScope_ConcurrentResolveBuildsOnce(t *testing.T) {
+	c := New()
+
+	var builds atomic.Int32
+
+	require.NoError(t, RegisterScoped(c, "session", func(c Vessel) (*testService, error) {
+		builds.Add(1)
+		time.Sleep(10 * time.Millisecond)
+
+		return &testService{value: "session"}, nil
+	}))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	var wg sync.WaitGroup
+
+	results := make([]*testService, 10)
+
+	for i := range results {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i], _ = ResolveScope[*testService](s, "session")
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), builds.Load())
+
+	for _, result := range results {
+		assert.Same(t, results[0], result)
+	}
+}
+
+func TestScope_IndependentServicesResolveInParallel(t *testing.T) {
+	c := New()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	// Each factory only returns once both are running
+	factory := func(c Vessel) (any, error) {
+		started <- struct{}{}
+		<-release
+
+		return &testService{}, nil
+	}
+
+	require.NoError(t, c.Register("a", factory, Scoped()))
+	require.NoError(t, c.Register("b", factory, Scoped()))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	var wg sync.WaitGroup
+
+	for _, name := range []string{"a", "b"} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := s.Resolve(name)
+			assert.NoError(t, err)
+		}()
+	}
+
+	for range 2 {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("scoped factories were serialized")
+		}
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestScope_RuntimeCycle(t *testing.T) {
+	c := New()
+
+	dependOn := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return c.Resolve(name)
+		}
+	}
+
+	require.NoError(t, c.Register("a", dependOn("b"), Scoped()))
+	require.NoError(t, c.Register("b", dependOn("a"), Scoped()))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	_, err := s.Resolve("a")
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[a b a]")
+
+	// Nothing was cached, and the scope still works
+	assert.Empty(t, s.(*scope).Services())
+	assert.Empty(t, s.(*scope).pending)
+}
+
+func TestScope_RuntimeTransientCycle(t *testing.T) {
+	c := New()
+
+	dependOn := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return c.Resolve(name)
+		}
+	}
+
+	require.NoError(t, c.Register("a", dependOn("b"), Transient()))
+	require.NoError(t, c.Register("b", dependOn("a"), Transient()))
+	require.NoError(t, c.Register("session", dependOn("temp"), Scoped()))
+	require.NoError(t, c.Register("temp", dependOn("session"), Transient()))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	_, err := s.Resolve("a")
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[a b a]")
+
+	// Transients show up in cycles through scoped services too
+	_, err = s.Resolve("session")
+	require.ErrorIs(t, err, ErrCircularDependencySentinel)
+	assert.Contains(t, err.Error(), "[session temp session]")
+	assert.Empty(t, s.(*scope).pending)
+}
+
+func TestScope_RuntimeCycleAcrossGoroutines(t *testing.T) {
+	c := New()
+	var entered sync.WaitGroup
+	entered.Add(2)
+
+	// Both factories start before either resolves the other
+	dependOn := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			entered.Done()
+			entered.Wait()
+
+			return c.Resolve(name)
+		}
+	}
+
+	require.NoError(t, c.Register("a", dependOn("b"), Scoped()))
+	require.NoError(t, c.Register("b", dependOn("a"), Scoped()))
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	errs := make(chan error, 2)
+
+	for _, name := range []string{"a", "b"} {
+		go func() {
+			_, err := s.Resolve(name)
+			errs <- err
+		}()
+	}
+
+	for range 2 {
+		select {
+		case err := <-errs:
+			assert.ErrorIs(t, err, ErrCircularDependencySentinel)
+		case <-time.After(time.Second):
+			t.Fatal("scoped resolution deadlocked")
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package vessel
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 
@@ -229,3 +231,273 @@ func TestScope_ConcurrentResolve(t *testing.T) {
 	assert.Equal(t, 1, callCount)
 	mu.Unlock()
 }
+
+func TestScope_End_ReverseCreationOrder(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	closer := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return &recordingCloser{name: name, events: &events}, nil
+		}
+	}
+
+	require.NoError(t, c.Register("tx", closer("tx"), Scoped()))
+	require.NoError(t, c.Register("repo", closer("repo"), Scoped(), WithDependencies("tx")))
+	require.NoError(t, c.Register("logger", func(c Vessel) (any, error) {
+		return &recordingDisposable{recordingCloser{name: "logger", events: &events}}, nil
+	}, Scoped()))
+	require.NoError(t, c.Register("audit", closer("audit"), Scoped()))
+
+	scope := c.BeginScope()
+
+	// repo is created before tx, but still disposed first because it depends on tx
+	for _, name := range []string{"logger", "repo", "audit", "tx"} {
+		_, err := scope.Resolve(name)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, scope.End())
+	assert.Equal(t, []string{"close repo", "close tx", "close audit", "dispose logger"}, events)
+}
+
+func TestScope_End_JoinsErrors(t *testing.T) {
+	c := New()
+	events := []string{}
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+
+	require.NoError(t, c.Register("a", func(c Vessel) (any, error) {
+		return &recordingCloser{name: "a", events: &events, err: errA}, nil
+	}, Scoped()))
+	require.NoError(t, c.Register("b", func(c Vessel) (any, error) {
+		return &recordingCloser{name: "b", events: &events, err: errB}, nil
+	}, Scoped()))
+
+	scope := c.BeginScope()
+	_, _ = scope.Resolve("a")
+	_, _ = scope.Resolve("b")
+
+	err := scope.End()
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+	assert.Equal(t, []string{"close b", "close a"}, events)
+}
+
+func TestScope_StartsAndStopsScopedServices(t *testing.T) {
+	c := New().(*containerImpl)
+	events := []string{}
+
+	c.Use(&FuncMiddleware{
+		BeforeStartFunc: func(ctx context.Context, name string) error {
+			events = append(events, "before "+name)
+
+			return nil
+		},
+		AfterStartFunc: func(ctx context.Context, name string, err error) error {
+			events = append(events, "after "+name)
+
+			return nil
+		},
+	})
+
+	track := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return &mockServiceWithCallback{
+				mockService: mockService{name: name},
+				onStart:     func() { events = append(events, "start "+name) },
+				onStop:      func() { events = append(events, "stop "+name) },
+			}, nil
+		}
+	}
+
+	require.NoError(t, c.Register("session", track("session"), Scoped()))
+	require.NoError(t, c.Register("worker", track("worker"), Scoped(), WithDependencies("session")))
+
+	scope := c.BeginScope()
+
+	worker, err := ResolveScope[*mockServiceWithCallback](scope, "worker")
+	require.NoError(t, err)
+	assert.True(t, worker.started)
+
+	_, err = scope.Resolve("session")
+	require.NoError(t, err)
+
+	require.NoError(t, scope.End())
+	assert.Equal(t, []string{
+		"before worker", "start worker", "after worker",
+		"before session", "start session", "after session",
+		"stop worker", "stop session",
+	}, events)
+	assert.True(t, worker.stopped)
+	assert.True(t, worker.disposed, "stopped services are still disposed")
+}
+
+func TestScope_StartFailure(t *testing.T) {
+	c := New()
+	startErr := errors.New("start failed")
+	var built []*mockService
+
+	require.NoError(t, c.Register("worker", func(c Vessel) (any, error) {
+		worker := &mockService{name: "worker", startErr: startErr}
+		built = append(built, worker)
+
+		return worker, nil
+	}, Scoped()))
+
+	s := c.BeginScope().(*scope)
+
+	_, err := s.Resolve("worker")
+	assert.ErrorIs(t, err, startErr)
+
+	// The failed instance isn't cached, so the next resolve retries
+	_, err = s.Resolve("worker")
+	assert.ErrorIs(t, err, startErr)
+	require.Len(t, built, 2)
+	assert.Empty(t, s.Services())
+
+	// End won't see them, so they were released when they failed
+	for _, worker := range built {
+		assert.True(t, worker.disposed)
+	}
+
+	require.NoError(t, s.End())
+}
+
+func TestScope_Nested(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	closer := func(name string) Factory {
+		return func(c Vessel) (any, error) {
+			return &recordingCloser{name: name, events: &events}, nil
+		}
+	}
+
+	require.NoError(t, c.Register("job", closer("job"), Scoped()))
+	require.NoError(t, c.Register("item", closer("item"), Scoped()))
+
+	job := c.BeginScope()
+	jobValue, err := job.Resolve("job")
+	require.NoError(t, err)
+
+	item, err := BeginNestedScope(job)
+	require.NoError(t, err)
+
+	inherited, err := item.Resolve("job")
+	require.NoError(t, err)
+	assert.Same(t, jobValue, inherited, "nested scopes reuse enclosing instances")
+
+	itemValue, err := item.Resolve("item")
+	require.NoError(t, err)
+
+	other := job.(*scope).BeginScope()
+	otherValue, err := other.Resolve("item")
+	require.NoError(t, err)
+	assert.NotSame(t, itemValue, otherValue, "sibling scopes have their own instances")
+	assert.Equal(t, []string{"item"}, item.(*scope).Services())
+	assert.Equal(t, []string{"job"}, job.(*scope).Services())
+
+	require.NoError(t, item.End())
+	assert.Equal(t, []string{"close item"}, events)
+
+	// Ending the enclosing scope ends the open nested scopes first
+	require.NoError(t, job.End())
+	assert.Equal(t, []string{"close item", "close item", "close job"}, events)
+	assert.True(t, other.(*scope).IsEnded())
+	assert.Empty(t, job.(*scope).children)
+}
+
+func TestScope_NestedContext(t *testing.T) {
+	c := New()
+	job := c.BeginScope()
+
+	SetScoped(job, "jobID", "j-1")
+	SetScoped(job, "attempt", 1)
+
+	item, err := BeginNestedScope(job)
+	require.NoError(t, err)
+
+	SetScoped(item, "attempt", 2)
+
+	jobID, ok := GetScoped[string](item, "jobID")
+	assert.True(t, ok)
+	assert.Equal(t, "j-1", jobID)
+
+	attempt, _ := GetScoped[int](item, "attempt")
+	assert.Equal(t, 2, attempt, "nested values shadow enclosing ones")
+
+	attempt, _ = GetScoped[int](job, "attempt")
+	assert.Equal(t, 1, attempt)
+
+	require.NoError(t, item.End())
+
+	_, ok = GetScoped[string](item, "jobID")
+	assert.False(t, ok)
+	require.NoError(t, job.End())
+}
+
+func TestBeginNestedScope_Errors(t *testing.T) {
+	c := New()
+	job := c.BeginScope()
+	require.NoError(t, job.End())
+
+	item, err := BeginNestedScope(job)
+	require.NoError(t, err)
+	assert.True(t, item.(*scope).IsEnded())
+
+	_, err = item.Resolve("anything")
+	assert.ErrorIs(t, err, ErrScopeEnded)
+
+	_, err = BeginNestedScope(nil)
+	assert.Error(t, err)
+}
+
+func TestScope_Provide(t *testing.T) {
+	c := New()
+	events := []string{}
+
+	require.NoError(t, RegisterSingleton(c, "user", func(c Vessel) (*testService, error) {
+		return &testService{value: "anonymous"}, nil
+	}))
+	require.NoError(t, RegisterScoped(c, "greeting", func(c Vessel) (*testService, error) {
+		user, err := Resolve[*testService](c, "user")
+		if err != nil {
+			return nil, err
+		}
+
+		return &testService{value: "hello " + user.value}, nil
+	}))
+
+	s := c.BeginScope()
+
+	local := &recordingCloser{name: "conn", events: &events}
+	require.NoError(t, ProvideInScope(s, "user", &testService{value: "alice"}))
+	require.NoError(t, ProvideInScope(s, "conn", local))
+	assert.ErrorIs(t, ProvideInScope(s, "conn", local), ErrServiceAlreadyExists("conn"))
+	assert.True(t, s.(*scope).Has("conn"))
+
+	// Provided values shadow the container, for factories too
+	greeting, err := ResolveScope[*testService](s, "greeting")
+	require.NoError(t, err)
+	assert.Equal(t, "hello alice", greeting.value)
+
+	conn, err := s.Resolve("conn")
+	require.NoError(t, err)
+	assert.Same(t, local, conn)
+
+	nested, err := BeginNestedScope(s)
+	require.NoError(t, err)
+
+	user, err := ResolveScope[*testService](nested, "user")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.value, "nested scopes see provided values")
+
+	user, err = Resolve[*testService](c, "user")
+	require.NoError(t, err)
+	assert.Equal(t, "anonymous", user.value, "the container is unaffected")
+
+	require.NoError(t, s.End())
+	assert.Empty(t, events, "provided values are owned by the caller")
+	assert.ErrorIs(t, ProvideInScope(s, "late", 1), ErrScopeEnded)
+}
@@ -0,0 +1,381 @@
+package vessel
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"weak"
+)
+
+// DefaultScopeLeakInterval is how often the leak detector looks for scopes
+// older than ScopeLeakOptions.MaxAge when Interval is not set.
+const DefaultScopeLeakInterval = time.Minute
+
+// ScopeInfo describes a scope that has not been ended.
+type ScopeInfo struct {
+	ID        uint64
+	CreatedAt time.Time
+	Age       time.Duration
+	Services  []string // Scoped services resolved in the scope, in creation order
+	Stack     string   // Where the scope began
+}
+
+// ScopeLeakReason says why the leak detector reported a scope.
+type ScopeLeakReason string
+
+const (
+	// ScopeLeakMaxAge means the scope is still open after MaxAge.
+	ScopeLeakMaxAge ScopeLeakReason = "max_age"
+
+	// ScopeLeakCollected means the scope was garbage collected without End,
+	// so its instances were never stopped or disposed.
+	ScopeLeakCollected ScopeLeakReason = "collected"
+)
+
+// ScopeLeak is a scope reported by the leak detector.
+type ScopeLeak struct {
+	Scope  ScopeInfo
+	Reason ScopeLeakReason
+}
+
+// ScopeLeakOptions configures the scope leak detector.
+type ScopeLeakOptions struct {
+	// MaxAge after which an open scope is reported, once. Zero disables
+	// age checks, leaving only scopes collected without End.
+	MaxAge time.Duration
+
+	// Interval between age checks. Defaults to DefaultScopeLeakInterval.
+	Interval time.Duration
+
+	// OnLeak is called for each leaked scope, from the detector goroutine
+	// or the garbage collector's cleanup goroutine. By default leaks are
+	// logged with the stack that began the scope.
+	OnLeak func(leak ScopeLeak)
+}
+
+// WithScopeTracking records every scope begun on the container until it
+// ends, for ActiveScopes. Each scope records the stack that began it, which
+// makes BeginScope several times slower.
+func WithScopeTracking() ContainerOption {
+	return func(o *containerOptions) {
+		o.scopeTracking = true
+	}
+}
+
+// WithScopeLeakDetection tracks scopes like WithScopeTracking and reports
+// those that stay open longer than MaxAge while the container is started,
+// and those garbage collected without End.
+//
+// Example:
+//
+//	c := vessel.New(vessel.WithScopeLeakDetection(vessel.ScopeLeakOptions{
+//	    MaxAge: 5 * time.Minute,
+//	}))
+func WithScopeLeakDetection(opts ScopeLeakOptions) ContainerOption {
+	return func(o *containerOptions) {
+		o.scopeTracking = true
+		o.scopeLeaks = &opts
+	}
+}
+
+// ActiveScopes returns the scopes begun on the container that have not
+// ended yet, oldest first. See (*containerImpl).ActiveScopes.
+func ActiveScopes(c Vessel) ([]ScopeInfo, error) {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return nil, fmt.Errorf("ActiveScopes requires *containerImpl, got %T", c)
+	}
+
+	if impl.scopes == nil {
+		return nil, fmt.Errorf("container does not track scopes")
+	}
+
+	return impl.ActiveScopes(), nil
+}
+
+// ActiveScopes returns the scopes begun on the container, including nested
+// ones, that have not ended yet, oldest first. A scope dropped without End
+// stays listed until it is garbage collected. It returns nil unless scope
+// tracking is enabled.
+func (c *containerImpl) ActiveScopes() []ScopeInfo {
+	return c.scopes.active()
+}
+
+// trackedScope is the tracker's record of a scope. It only holds a weak
+// pointer to the scope, so a forgotten scope can still be collected.
+type trackedScope struct {
+	id        uint64
+	scope     weak.Pointer[scope]
+	createdAt time.Time
+	stack     []uintptr
+	services  []string
+	reported  bool // Already reported for MaxAge
+}
+
+// info describes the scope at now. The caller must hold the tracker's lock.
+func (t *trackedScope) info(now time.Time) ScopeInfo {
+	return ScopeInfo{
+		ID:        t.id,
+		CreatedAt: t.createdAt,
+		Age:       now.Sub(t.createdAt),
+		Services:  append([]string(nil), t.services...),
+		Stack:     formatStack(t.stack),
+	}
+}
+
+// scopeTracker records a container's open scopes and, when enabled, runs
+// the leak detector. A nil tracker tracks nothing.
+type scopeTracker struct {
+	leaks   *ScopeLeakOptions // nil unless leak detection is enabled
+	scopes  map[uint64]*trackedScope
+	nextID  uint64
+	pruneAt int // Scope count at which collected scopes are next pruned
+	cancel  context.CancelFunc
+	done    chan struct{}
+	mu      sync.Mutex
+}
+
+// newScopeTracker applies defaults to leaks, which may be nil.
+func newScopeTracker(leaks *ScopeLeakOptions) *scopeTracker {
+	if leaks != nil {
+		opts := *leaks
+		if opts.Interval <= 0 {
+			opts.Interval = DefaultScopeLeakInterval
+		}
+
+		if opts.OnLeak == nil {
+			opts.OnLeak = logScopeLeak
+		}
+
+		leaks = &opts
+	}
+
+	return &scopeTracker{
+		leaks:   leaks,
+		scopes:  make(map[uint64]*trackedScope),
+		pruneAt: minScopePrune,
+	}
+}
+
+// minScopePrune is the smallest scope count that triggers pruning.
+const minScopePrune = 64
+
+// track starts tracking s until it ends or is garbage collected.
+//
+// Collected scopes are dropped lazily, when the number of tracked scopes
+// has doubled or ActiveScopes is called. With leak detection, a cleanup
+// also reports each one as soon as it is collected.
+func (t *scopeTracker) track(s *scope) {
+	if t == nil {
+		return
+	}
+
+	record := &trackedScope{scope: weak.Make(s), createdAt: time.Now()}
+
+	pcs := make([]uintptr, 32)
+	record.stack = pcs[:runtime.Callers(3, pcs)]
+
+	t.mu.Lock()
+	t.nextID++
+	record.id = t.nextID
+	t.scopes[record.id] = record
+
+	if len(t.scopes) >= t.pruneAt {
+		t.prune()
+		t.pruneAt = max(2*len(t.scopes), minScopePrune)
+	}
+	t.mu.Unlock()
+
+	s.tracked = record
+
+	if t.leaks != nil {
+		runtime.AddCleanup(s, t.collected, record.id)
+	}
+}
+
+// prune drops the records of collected scopes. The caller must hold t.mu.
+func (t *scopeTracker) prune() {
+	for id, record := range t.scopes {
+		if record.scope.Value() == nil {
+			delete(t.scopes, id)
+		}
+	}
+}
+
+// resolved records that the tracked scope created a scoped instance.
+func (t *scopeTracker) resolved(record *trackedScope, name string) {
+	if t == nil || record == nil {
+		return
+	}
+
+	t.mu.Lock()
+	record.services = append(record.services, name)
+	t.mu.Unlock()
+}
+
+// untrack stops tracking an ended scope.
+func (t *scopeTracker) untrack(record *trackedScope) {
+	if t == nil || record == nil {
+		return
+	}
+
+	t.mu.Lock()
+	delete(t.scopes, record.id)
+	t.mu.Unlock()
+}
+
+// collected handles a scope garbage collected while still tracked, that
+// is, without End.
+func (t *scopeTracker) collected(id uint64) {
+	t.mu.Lock()
+	record, ok := t.scopes[id]
+	delete(t.scopes, id)
+
+	var leak ScopeLeak
+	if ok {
+		leak = ScopeLeak{Scope: record.info(time.Now()), Reason: ScopeLeakCollected}
+	}
+	t.mu.Unlock()
+
+	if ok && t.leaks != nil {
+		t.leaks.OnLeak(leak)
+	}
+}
+
+// active describes the tracked scopes, oldest first.
+func (t *scopeTracker) active() []ScopeInfo {
+	if t == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	t.mu.Lock()
+
+	t.prune()
+
+	infos := make([]ScopeInfo, 0, len(t.scopes))
+	for _, record := range t.scopes {
+		infos = append(infos, record.info(now))
+	}
+
+	t.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+
+	return infos
+}
+
+// start launches the age check loop if leak detection has a MaxAge.
+func (t *scopeTracker) start() {
+	if t == nil || t.leaks == nil || t.leaks.MaxAge <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.cancel, t.done = cancel, done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(t.leaks.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.checkAge()
+			}
+		}
+	}()
+}
+
+// stop ends the age check loop and waits for it to exit.
+func (t *scopeTracker) stop() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	cancel, done := t.cancel, t.done
+	t.cancel, t.done = nil, nil
+	t.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// checkAge reports scopes that have been open longer than MaxAge.
+func (t *scopeTracker) checkAge() {
+	now := time.Now()
+
+	var leaks []ScopeLeak
+
+	t.mu.Lock()
+
+	for _, record := range t.scopes {
+		if !record.reported && now.Sub(record.createdAt) > t.leaks.MaxAge {
+			record.reported = true
+			leaks = append(leaks, ScopeLeak{Scope: record.info(now), Reason: ScopeLeakMaxAge})
+		}
+	}
+
+	t.mu.Unlock()
+
+	sort.Slice(leaks, func(i, j int) bool {
+		return leaks[i].Scope.ID < leaks[j].Scope.ID
+	})
+
+	for _, leak := range leaks {
+		t.leaks.OnLeak(leak)
+	}
+}
+
+// logScopeLeak is the default OnLeak.
+func logScopeLeak(leak ScopeLeak) {
+	log.Printf("vessel: scope %d leaked (%s) after %s with services %v, begun at:\n%s",
+		leak.Scope.ID, leak.Reason, leak.Scope.Age.Round(time.Millisecond), leak.Scope.Services, leak.Scope.Stack)
+}
+
+// formatStack renders program counters as "function\n\tfile:line" lines.
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
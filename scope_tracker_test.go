@@ -0,0 +1,116 @@
+package vessel
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leakRecorder collects leaks reported from background goroutines.
+type leakRecorder struct {
+	leaks []ScopeLeak
+	mu    sync.Mutex
+}
+
+func (r *leakRecorder) record(leak ScopeLeak) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.leaks = append(r.leaks, leak)
+}
+
+func (r *leakRecorder) get() []ScopeLeak {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]ScopeLeak(nil), r.leaks...)
+}
+
+func TestActiveScopes(t *testing.T) {
+	_, err := ActiveScopes(New())
+	assert.Error(t, err, "tracking is opt-in")
+
+	c := New(WithScopeTracking())
+	require.NoError(t, RegisterScoped(c, "session", func(c Vessel) (*testService, error) {
+		return &testService{}, nil
+	}))
+
+	job := c.BeginScope()
+	_, err = job.Resolve("session")
+	require.NoError(t, err)
+
+	item, err := BeginNestedScope(job)
+	require.NoError(t, err)
+
+	scopes, err := ActiveScopes(c)
+	require.NoError(t, err)
+	require.Len(t, scopes, 2)
+	assert.Equal(t, []string{"session"}, scopes[0].Services)
+	assert.Empty(t, scopes[1].Services, "inherited instances belong to the enclosing scope")
+	assert.Contains(t, scopes[0].Stack, "TestActiveScopes")
+	assert.Positive(t, scopes[0].Age)
+
+	require.NoError(t, item.End())
+	assert.Len(t, c.(*containerImpl).ActiveScopes(), 1)
+
+	require.NoError(t, job.End())
+	assert.Empty(t, c.(*containerImpl).ActiveScopes())
+}
+
+func TestScopeLeakDetection_MaxAge(t *testing.T) {
+	recorder := &leakRecorder{}
+
+	c := New(WithScopeLeakDetection(ScopeLeakOptions{
+		MaxAge:   10 * time.Millisecond,
+		Interval: 5 * time.Millisecond,
+		OnLeak:   recorder.record,
+	}))
+	require.NoError(t, c.Start(context.Background()))
+
+	defer func() { _ = c.Stop(context.Background()) }()
+
+	s := c.BeginScope()
+	defer func() { _ = s.End() }()
+
+	require.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, time.Millisecond)
+
+	// Each scope is reported once
+	time.Sleep(20 * time.Millisecond)
+
+	leaks := recorder.get()
+	require.Len(t, leaks, 1)
+	assert.Equal(t, ScopeLeakMaxAge, leaks[0].Reason)
+	assert.GreaterOrEqual(t, leaks[0].Scope.Age, 10*time.Millisecond)
+	assert.Contains(t, leaks[0].Scope.Stack, "TestScopeLeakDetection_MaxAge")
+}
+
+func TestScopeLeakDetection_Collected(t *testing.T) {
+	recorder := &leakRecorder{}
+
+	c := New(WithScopeLeakDetection(ScopeLeakOptions{OnLeak: recorder.record}))
+	require.NoError(t, RegisterScoped(c, "conn", func(c Vessel) (*testService, error) {
+		return &testService{}, nil
+	}))
+
+	// Forget a scope, and properly end another
+	func() {
+		_, _ = c.BeginScope().Resolve("conn")
+		require.NoError(t, c.BeginScope().End())
+	}()
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+
+		return len(recorder.get()) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	leak := recorder.get()[0]
+	assert.Equal(t, ScopeLeakCollected, leak.Reason)
+	assert.Equal(t, []string{"conn"}, leak.Scope.Services)
+	assert.Empty(t, c.(*containerImpl).ActiveScopes())
+}
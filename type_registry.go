@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 )
 
 // typeKey uniquely identifies a service by its type and optional name.
@@ -34,10 +35,17 @@ type typeRegistration struct {
 	instance     any
 	lifecycle    string // "singleton", "transient", "scoped"
 	groups       []string
-	constructing bool // Prevent circular instantiation
+	decorators   []serviceDecorator // Applied to each new instance, in order
+	constructing bool               // Prevent circular instantiation
+	createdSeq   uint64             // Creation order of the cached instance, for disposal
+	container    *containerImpl     // Container running the lifecycle hooks of the instance
 	mu           sync.RWMutex
 }
 
+// creationSeq orders cached type-based singletons by creation. A singleton
+// is always cached after the dependencies its constructor resolved.
+var creationSeq atomic.Uint64
+
 // typeRegistry manages type-based service registrations alongside the
 // existing name-based registry. This enables dig-like constructor injection.
 type typeRegistry struct {
@@ -73,20 +81,144 @@ func (r *typeRegistry) register(key typeKey, reg *typeRegistration) error {
 	return nil
 }
 
-// get retrieves a type registration by key
-func (r *typeRegistry) get(key typeKey) (*typeRegistration, bool) {
+// replace adds or overwrites a type-based registration, moving group
+// membership from the previous registration to the new one.
+func (r *typeRegistry) replace(key typeKey, reg *typeRegistration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, exists := r.services[key]; exists && old != reg {
+		for group, members := range r.groups {
+			r.groups[group] = removeRegistration(members, old)
+		}
+	}
+
+	r.services[key] = reg
+
+	for _, group := range reg.groups {
+		if !containsRegistration(r.groups[group], reg) {
+			r.groups[group] = append(r.groups[group], reg)
+		}
+	}
+
+	return nil
+}
+
+// swap points every key and group entry that refers to old at reg instead,
+// so aliases keep sharing a single registration.
+func (r *typeRegistry) swap(old, reg *typeRegistration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, current := range r.services {
+		if current == old {
+			r.services[key] = reg
+		}
+	}
+
+	// Rebuild group slices rather than editing them, as getGroup hands them out
+	for group, members := range r.groups {
+		if !containsRegistration(members, old) {
+			continue
+		}
+
+		swapped := make([]*typeRegistration, len(members))
+		for i, member := range members {
+			if member == old {
+				member = reg
+			}
+			swapped[i] = member
+		}
+		r.groups[group] = swapped
+	}
+}
+
+// snapshot copies the registry's current registrations and groups.
+func (r *typeRegistry) snapshot() *typeRegistry {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	reg, ok := r.services[key]
-	return reg, ok
+
+	clone := newTypeRegistry()
+	for key, reg := range r.services {
+		clone.services[key] = reg
+	}
+	for group, members := range r.groups {
+		clone.groups[group] = append([]*typeRegistration(nil), members...)
+	}
+
+	return clone
+}
+
+// restore replaces the registry's contents with a copy of from.
+func (r *typeRegistry) restore(from *typeRegistry) {
+	clone := from.snapshot()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.services = clone.services
+	r.groups = clone.groups
+}
+
+// registrations returns the distinct registrations in the registry.
+func (r *typeRegistry) registrations() []*typeRegistration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[*typeRegistration]bool, len(r.services))
+	regs := make([]*typeRegistration, 0, len(r.services))
+
+	for _, reg := range r.services {
+		if !seen[reg] {
+			seen[reg] = true
+			regs = append(regs, reg)
+		}
+	}
+
+	return regs
+}
+
+// holds reports whether any key still refers to reg.
+func (r *typeRegistry) holds(reg *typeRegistration) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, current := range r.services {
+		if current == reg {
+			return true
+		}
+	}
+
+	return false
 }
 
-// has checks if a type is registered
-func (r *typeRegistry) has(key typeKey) bool {
+// containsRegistration reports whether reg is in regs.
+func containsRegistration(regs []*typeRegistration, reg *typeRegistration) bool {
+	for _, r := range regs {
+		if r == reg {
+			return true
+		}
+	}
+	return false
+}
+
+// removeRegistration returns regs without reg.
+func removeRegistration(regs []*typeRegistration, reg *typeRegistration) []*typeRegistration {
+	result := regs[:0:0]
+	for _, r := range regs {
+		if r != reg {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// get retrieves a type registration by key
+func (r *typeRegistry) get(key typeKey) (*typeRegistration, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	_, ok := r.services[key]
-	return ok
+	reg, ok := r.services[key]
+	return reg, ok
 }
 
 // getGroup returns all registrations in a group
@@ -127,8 +259,21 @@ func (reg *typeRegistration) resolve(container Vessel) (any, error) {
 	reg.constructing = true
 	reg.mu.Unlock() // Release lock before calling factory to avoid deadlock
 
-	// Call factory (without holding lock)
+	// Hooks appended while building a singleton belong to its type
+	singleton := reg.lifecycle == "singleton"
+	if singleton {
+		container = &typeResolution{Vessel: container, owner: reg.key}
+	}
+
+	// Call factory and decorators (without holding lock)
 	instance, err := reg.factory(container)
+	for _, decorate := range reg.decorators {
+		if err != nil {
+			break
+		}
+
+		instance, err = decorate(container, instance)
+	}
 
 	// Re-acquire lock to update state
 	reg.mu.Lock()
@@ -140,10 +285,17 @@ func (reg *typeRegistration) resolve(container Vessel) (any, error) {
 	}
 
 	// Cache for singletons
-	if reg.lifecycle == "singleton" {
+	if singleton {
 		reg.instance = instance
+		reg.createdSeq = creationSeq.Add(1)
 	}
 	reg.mu.Unlock()
 
+	if singleton && reg.container != nil {
+		if err := reg.container.startTypeHooks(reg.key); err != nil {
+			return nil, err
+		}
+	}
+
 	return instance, nil
 }
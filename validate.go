@@ -0,0 +1,294 @@
+package vessel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationIssueKind classifies a problem found by Validate.
+type ValidationIssueKind string
+
+const (
+	// IssueMissingDependency is a declared dependency that is not registered.
+	IssueMissingDependency ValidationIssueKind = "missing_dependency"
+
+	// IssueCircularDependency is a cycle in the declared dependencies.
+	IssueCircularDependency ValidationIssueKind = "circular_dependency"
+
+	// IssueUnsatisfiedParameter is a constructor parameter with no provider.
+	IssueUnsatisfiedParameter ValidationIssueKind = "unsatisfied_parameter"
+
+	// IssueUnsatisfiedField is an In struct field with no provider.
+	IssueUnsatisfiedField ValidationIssueKind = "unsatisfied_field"
+
+	// IssueCaptiveDependency is a singleton depending on a shorter-lived
+	// service, reported when the container's CaptivePolicy is CaptiveError.
+	IssueCaptiveDependency ValidationIssueKind = "captive_dependency"
+)
+
+// ValidationIssue describes one wiring problem.
+type ValidationIssue struct {
+	Kind ValidationIssueKind
+
+	// Service is the service name, or the type key of a constructor result.
+	Service string
+
+	// Dependency is the missing service name or type key.
+	Dependency string
+
+	// Cycle is the dependency path for circular dependencies, starting and
+	// ending with the same service.
+	Cycle []string
+
+	Message string
+}
+
+// ValidationError is returned by Validate and lists every issue found.
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Issues)+1)
+	lines = append(lines, fmt.Sprintf("container validation failed with %d issue(s):", len(e.Issues)))
+
+	for _, issue := range e.Issues {
+		lines = append(lines, "  - "+issue.Message)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the container's wiring without running any factory.
+// It reports every declared dependency that is not registered, every
+// dependency cycle, every ProvideConstructor parameter type with no
+// provider and every In struct field with no provider. Optional
+// dependencies are allowed to be missing; dependencies registered in a
+// parent container count as registered. Captive dependencies are checked
+// according to the container's CaptivePolicy: reported as issues in
+// CaptiveError mode and passed to OnCaptive in CaptiveWarn mode.
+//
+// It returns nil when the container is valid, otherwise a *ValidationError.
+//
+// Example:
+//
+//	func TestWiring(t *testing.T) {
+//	    c := app.NewContainer()
+//	    if err := vessel.Validate(c); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func Validate(c Vessel) error {
+	impl, ok := containerOf(c).(*containerImpl)
+	if !ok {
+		return fmt.Errorf("Validate requires *containerImpl, got %T", c)
+	}
+
+	return impl.Validate()
+}
+
+// Validate checks the container's wiring without running any factory.
+func (c *containerImpl) Validate() error {
+	var issues []ValidationIssue
+
+	issues = append(issues, c.validateDependencies()...)
+	issues = append(issues, c.validateConstructors()...)
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Issues: issues}
+}
+
+// validateDependencies checks name-based registrations for missing
+// dependencies and cycles.
+func (c *containerImpl) validateDependencies() []ValidationIssue {
+	c.mu.RLock()
+
+	type missing struct{ service, dep string }
+
+	var (
+		unresolved []missing
+		captive    []CaptiveDependency
+	)
+
+	policy := c.options.captive
+	parent := c.parentImpl()
+
+	for _, name := range c.graph.order {
+		reg, exists := c.services[name]
+		if !exists || reg.name != name {
+			// Module aliases share their target's registration
+			continue
+		}
+
+		for _, dep := range reg.deps {
+			target, ok := c.services[dep.Name]
+			if !ok && parent != nil {
+				target, _, ok = parent.lookup(dep.Name)
+			}
+
+			if ok && policy.Mode != CaptiveOff && policy.captures(reg, dep, target) {
+				captive = append(captive, newCaptiveDependency(reg, target))
+			}
+
+			if !ok && !dep.Mode.IsOptional() {
+				unresolved = append(unresolved, missing{service: name, dep: dep.Name})
+			}
+		}
+	}
+
+	cycles := c.graph.cycles()
+
+	c.mu.RUnlock()
+
+	var issues []ValidationIssue
+
+	// Checked without the lock, as the parent may be any container
+	for _, m := range unresolved {
+		if c.parent != nil && c.parent.Has(m.dep) {
+			continue
+		}
+
+		issues = append(issues, ValidationIssue{
+			Kind:       IssueMissingDependency,
+			Service:    m.service,
+			Dependency: m.dep,
+			Message:    fmt.Sprintf("service '%s' depends on '%s', which is not registered", m.service, m.dep),
+		})
+	}
+
+	if policy.Mode == CaptiveWarn {
+		policy.warnCaptive(captive)
+	} else {
+		for _, dep := range captive {
+			issues = append(issues, ValidationIssue{
+				Kind:       IssueCaptiveDependency,
+				Service:    dep.Service,
+				Dependency: dep.Dependency,
+				Message:    ErrCaptiveDependency(dep).Error(),
+			})
+		}
+	}
+
+	for _, cycle := range cycles {
+		issues = append(issues, ValidationIssue{
+			Kind:    IssueCircularDependency,
+			Service: cycle[0],
+			Cycle:   cycle,
+			Message: "circular dependency: " + strings.Join(cycle, " -> "),
+		})
+	}
+
+	return issues
+}
+
+// validateConstructors checks that every constructor parameter and In
+// field of the type registry has a provider.
+func (c *containerImpl) validateConstructors() []ValidationIssue {
+	if c.typeRegistry == nil {
+		return nil
+	}
+
+	c.typeRegistry.mu.RLock()
+
+	keys := make([]typeKey, 0, len(c.typeRegistry.services))
+	regs := make(map[typeKey]*typeRegistration, len(c.typeRegistry.services))
+
+	for key, reg := range c.typeRegistry.services {
+		keys = append(keys, key)
+		regs[key] = reg
+	}
+
+	c.typeRegistry.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	var issues []ValidationIssue
+
+	// Aliases, As types and Out fields share a constructor; check it once
+	checked := make(map[*constructorInfo]bool)
+
+	for _, key := range keys {
+		info := regs[key].constructor
+		if info == nil || checked[info] {
+			continue
+		}
+
+		checked[info] = true
+
+		for _, param := range info.params {
+			if !param.isIn {
+				if !c.providesParam(param) {
+					issues = append(issues, ValidationIssue{
+						Kind:       IssueUnsatisfiedParameter,
+						Service:    key.String(),
+						Dependency: paramKey(param),
+						Message:    fmt.Sprintf("constructor of %s: no provider for parameter %s", key, paramKey(param)),
+					})
+				}
+
+				continue
+			}
+
+			for _, field := range param.inFields {
+				if !c.providesParam(field) {
+					issues = append(issues, ValidationIssue{
+						Kind:       IssueUnsatisfiedField,
+						Service:    key.String(),
+						Dependency: paramKey(field),
+						Message: fmt.Sprintf("constructor of %s: no provider for field %s.%s (%s)",
+							key, param.typ, fieldName(param, field), paramKey(field)),
+					})
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// providesParam reports whether param can be resolved, mirroring
+// resolveParam and resolveGroup.
+func (c *containerImpl) providesParam(param paramInfo) bool {
+	if param.optional {
+		return true
+	}
+
+	if param.group {
+		return len(c.findGroup(param.groupKey)) > 0
+	}
+
+	if param.typ == lifecycleType && param.name == "" {
+		return true
+	}
+
+	_, ok := c.findType(typeKey{typ: param.typ, name: param.name})
+
+	return ok
+}
+
+// paramKey describes what a parameter resolves to.
+func paramKey(param paramInfo) string {
+	if param.group {
+		return fmt.Sprintf("%s[group=%s]", param.typ, param.groupKey)
+	}
+
+	return typeKey{typ: param.typ, name: param.name}.String()
+}
+
+// fieldName returns the name of an In struct field.
+func fieldName(in, field paramInfo) string {
+	structType := in.typ
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	return structType.Field(field.index).Name
+}
@@ -0,0 +1,113 @@
+package vessel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xraph/go-utils/di"
+)
+
+func TestValidate_ValidContainer(t *testing.T) {
+	c := New()
+	factoryCalls := 0
+
+	factory := func(c Vessel) (any, error) {
+		factoryCalls++
+
+		return &testService{}, nil
+	}
+
+	require.NoError(t, c.Register("db", factory))
+	require.NoError(t, c.Register("api", factory, WithDependencies("db")))
+	require.NoError(t, c.Register("cache", factory, di.WithDeps(di.Optional("redis"))))
+	require.NoError(t, ProvideConstructor(c, newTestDatabase))
+	require.NoError(t, ProvideConstructor(c, newTestLogger))
+	require.NoError(t, ProvideConstructor(c, newTestUserService))
+	require.NoError(t, ProvideConstructor(c, func(lc Lifecycle) *testCache { return &testCache{} }))
+
+	assert.NoError(t, Validate(c))
+	assert.Zero(t, factoryCalls, "validation must not run factories")
+}
+
+func TestValidate_ReportsEveryIssue(t *testing.T) {
+	c := New()
+	factory := func(c Vessel) (any, error) { return nil, nil }
+
+	require.NoError(t, c.Register("api", factory, WithDependencies("db", "cache")))
+	require.NoError(t, c.Register("a", factory, WithDependencies("b")))
+	require.NoError(t, c.Register("b", factory, WithDependencies("a")))
+	require.NoError(t, c.Register("lazy", factory, di.WithDeps(di.Lazy("queue"))))
+
+	type params struct {
+		In
+
+		Logger *testLogger
+		Caches []*testCache `group:"caches"`
+		Maybe  *testCache   `optional:"true"`
+	}
+
+	require.NoError(t, ProvideConstructor(c, newTestUserService))
+	require.NoError(t, ProvideConstructor(c, func(p params) *testService { return &testService{} }))
+
+	err := Validate(c)
+	require.Error(t, err)
+
+	var report *ValidationError
+	require.True(t, errors.As(err, &report))
+
+	byKind := map[ValidationIssueKind][]ValidationIssue{}
+	for _, issue := range report.Issues {
+		byKind[issue.Kind] = append(byKind[issue.Kind], issue)
+	}
+
+	missing := byKind[IssueMissingDependency]
+	require.Len(t, missing, 3)
+	assert.Equal(t, "api", missing[0].Service)
+	assert.Equal(t, "db", missing[0].Dependency)
+	assert.Equal(t, "cache", missing[1].Dependency)
+	assert.Equal(t, "queue", missing[2].Dependency, "lazy dependencies must still exist")
+
+	cycles := byKind[IssueCircularDependency]
+	require.Len(t, cycles, 1)
+	assert.Equal(t, []string{"a", "b", "a"}, cycles[0].Cycle)
+
+	// newTestUserService needs *testDatabase and *testLogger
+	params1 := byKind[IssueUnsatisfiedParameter]
+	require.Len(t, params1, 2)
+	assert.Equal(t, "*vessel.testDatabase", params1[0].Dependency)
+	assert.Equal(t, "*vessel.testLogger", params1[1].Dependency)
+
+	fields := byKind[IssueUnsatisfiedField]
+	require.Len(t, fields, 2)
+	assert.Contains(t, fields[0].Message, "Logger")
+	assert.Equal(t, "[]*vessel.testCache[group=caches]", fields[1].Dependency)
+
+	assert.Contains(t, err.Error(), "circular dependency: a -> b -> a")
+}
+
+func TestValidate_ChildUsesParent(t *testing.T) {
+	parent := New()
+	require.NoError(t, RegisterValue(parent, "db", &testService{}))
+	require.NoError(t, ProvideConstructor(parent, newTestDatabase))
+	require.NoError(t, ProvideConstructor(parent, newTestLogger))
+
+	child := NewChild(parent)
+	require.NoError(t, child.Register("api", func(c Vessel) (any, error) {
+		return &testService{}, nil
+	}, WithDependencies("db")))
+	require.NoError(t, ProvideConstructor(child, newTestUserService))
+
+	assert.NoError(t, Validate(child))
+}
+
+func TestValidate_MatchesStartFailure(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Register("a", func(c Vessel) (any, error) { return nil, nil }, WithDependencies("b")))
+	require.NoError(t, c.Register("b", func(c Vessel) (any, error) { return nil, nil }, WithDependencies("a")))
+
+	require.Error(t, Validate(c))
+	assert.Error(t, c.Start(context.Background()))
+}
@@ -1,6 +1,8 @@
 package vessel
 
 import (
+	"context"
+
 	"github.com/xraph/go-utils/di"
 )
 
@@ -14,10 +16,21 @@ type Scope = di.Scope
 // Factory creates a service instance.
 type Factory = di.Factory
 
+// ContextFactory creates a service instance using the context of the
+// resolution that triggered it, so deadlines, cancellation and
+// request-scoped values reach the factory.
+type ContextFactory func(ctx context.Context, c Vessel) (any, error)
+
+// ContextResolver is implemented by containers and scopes that can resolve
+// services with a caller-supplied context.
+type ContextResolver interface {
+	ResolveContext(ctx context.Context, name string) (any, error)
+}
+
 // ServiceInfo contains diagnostic information.
 type ServiceInfo = di.ServiceInfo
 
 // New creates a new DI container.
-func New() Vessel {
-	return newContainerImpl()
+func New(opts ...ContainerOption) Vessel {
+	return newContainerImpl(opts...)
 }